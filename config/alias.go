@@ -0,0 +1,116 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// Logger is the interface for printing debugging information and warnings (see WithLogger option).
+// *log.Logger implements this interface.
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+// getAliases returns the old names of a field specified using the aliases tag.
+// Aliases are environment variable names (i.e. DB_URL) and are not prefixed.
+func getAliases(f reflect.StructField) []string {
+	var aliases []string
+	for _, alias := range strings.Split(f.Tag.Get(tagAliases), ",") {
+		if alias = strings.TrimSpace(alias); alias != "" {
+			aliases = append(aliases, alias)
+		}
+	}
+
+	return aliases
+}
+
+// getAliasFlagName converts an alias to a flag name.
+//
+//	DB_URL  -->  db.url
+func getAliasFlagName(alias string) string {
+	return strings.ToLower(strings.ReplaceAll(alias, "_", "."))
+}
+
+// getAliasKeys returns the keys for looking up the value of a field by its aliases.
+// The names of an alias are only set for the sources that are not skipped for the field.
+//
+//	DB_URL  -->  -db.url  DB_URL  DB_URL_FILE  db_url (in the same table as the field)
+func (f fieldInfo) getAliasKeys(key Key) []Key {
+	keys := make([]Key, len(f.aliases))
+
+	for i, alias := range f.aliases {
+		k := key
+		k.Flag, k.Env, k.FileEnv, k.Path = "", "", "", nil
+
+		if key.Flag != "" {
+			k.Flag = getAliasFlagName(alias)
+		}
+
+		if key.Env != "" {
+			k.Env = alias
+		}
+
+		if key.FileEnv != "" {
+			k.FileEnv = alias + "_FILE"
+		}
+
+		if n := len(key.Path); n > 0 {
+			k.Path = append(append([]string{}, key.Path[:n-1]...), alias)
+		}
+
+		keys[i] = k
+	}
+
+	return keys
+}
+
+// getKeyName returns the name of a key used for reading a value from a source.
+func getKeyName(key Key, source string) string {
+	switch source {
+	case sourceFlag:
+		return "-" + key.Flag
+	case sourceEnv:
+		return key.Env
+	case sourceFile:
+		return key.FileEnv
+	case sourceDocument:
+		return strings.Join(key.Path, ".")
+	default:
+		return key.Field
+	}
+}
+
+// warnDeprecated warns about reading a value for a field using a deprecated name.
+// A name is deprecated if it is an alias or if the field has the deprecated tag.
+// Each deprecated name is only warned about once.
+func (r *reader) warnDeprecated(f fieldInfo, source string, key, used Key) {
+	isAlias := used.Env != key.Env || used.Flag != key.Flag || used.FileEnv != key.FileEnv || !slices.Equal(used.Path, key.Path)
+	if !isAlias && f.deprecated == "" {
+		return
+	}
+
+	name := getKeyName(used, source)
+	if r.warned[name] {
+		return
+	}
+
+	if r.warned == nil {
+		r.warned = map[string]bool{}
+	}
+	r.warned[name] = true
+
+	var msg string
+	if isAlias {
+		msg = fmt.Sprintf("%s %s is deprecated, use %s instead", source, name, getKeyName(key, source))
+	} else {
+		msg = fmt.Sprintf("%s %s is deprecated", source, name)
+	}
+
+	if f.deprecated != "" {
+		msg += ": " + f.deprecated
+	}
+
+	r.warn("[%s] %s", f.name, msg)
+}
@@ -0,0 +1,199 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testLogger struct {
+	msgs []string
+}
+
+func (l *testLogger) Printf(format string, v ...interface{}) {
+	l.msgs = append(l.msgs, fmt.Sprintf(format, v...))
+}
+
+func TestGetAliases(t *testing.T) {
+	tests := []struct {
+		name            string
+		f               reflect.StructField
+		expectedAliases []string
+	}{
+		{"NoTag", reflect.StructField{Name: "DatabaseURL"}, nil},
+		{"Alias", reflect.StructField{Name: "DatabaseURL", Tag: `aliases:"DB_URL"`}, []string{"DB_URL"}},
+		{"Aliases", reflect.StructField{Name: "DatabaseURL", Tag: `aliases:"DB_URL, OLD_DB_URL,"`}, []string{"DB_URL", "OLD_DB_URL"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedAliases, getAliases(tc.f))
+		})
+	}
+}
+
+func TestFieldInfoGetAliasKeys(t *testing.T) {
+	tests := []struct {
+		name         string
+		f            fieldInfo
+		expectedKeys []Key
+	}{
+		{
+			name: "NoAlias",
+			f: fieldInfo{
+				name:        "DatabaseURL",
+				flagName:    "database.url",
+				envName:     "DATABASE_URL",
+				fileEnvName: "DATABASE_URL_FILE",
+				docKeys:     []string{"DatabaseURL"},
+			},
+			expectedKeys: []Key{},
+		},
+		{
+			name: "Aliases",
+			f: fieldInfo{
+				name:        "Database.URL",
+				flagName:    "database.url",
+				envName:     "DATABASE_URL",
+				fileEnvName: "DATABASE_URL_FILE",
+				docKeys:     []string{"Database", "URL"},
+				aliases:     []string{"DB_URL", "OLD_DB_URL"},
+			},
+			expectedKeys: []Key{
+				{Field: "Database.URL", Flag: "db.url", Env: "DB_URL", FileEnv: "DB_URL_FILE", Path: []string{"Database", "DB_URL"}},
+				{Field: "Database.URL", Flag: "old.db.url", Env: "OLD_DB_URL", FileEnv: "OLD_DB_URL_FILE", Path: []string{"Database", "OLD_DB_URL"}},
+			},
+		},
+		{
+			name: "Skipped",
+			f: fieldInfo{
+				name:        "DatabaseURL",
+				flagName:    skip,
+				envName:     "DATABASE_URL",
+				fileEnvName: skip,
+				docKeys:     []string{skip},
+				aliases:     []string{"DB_URL"},
+			},
+			expectedKeys: []Key{
+				{Field: "DatabaseURL", Env: "DB_URL"},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedKeys, tc.f.getAliasKeys(tc.f.getKey()))
+		})
+	}
+}
+
+func TestPickWithAliases(t *testing.T) {
+	type aliasConfig struct {
+		AliasURL   string `aliases:"ALIAS_DB_URL,ALIAS_OLD_DB_URL" deprecated:"ALIAS_DB_URL will be removed in v2"`
+		AliasLevel string `aliases:"ALIAS_LOG_LEVEL"`
+		AliasDebug bool   `deprecated:"use AliasLevel instead"`
+		AliasPort  int    `aliases:"ALIAS_HTTP_PORT"`
+	}
+
+	env := map[string]string{
+		"ALIAS_DB_URL":     "postgres://old",
+		"ALIAS_OLD_DB_URL": "postgres://older",
+		"ALIAS_LOG_LEVEL":  "info",
+		"ALIAS_DEBUG":      "true",
+	}
+
+	for name, val := range env {
+		assert.NoError(t, os.Setenv(name, val))
+	}
+
+	defer func() {
+		for name := range env {
+			assert.NoError(t, os.Unsetenv(name))
+		}
+	}()
+
+	t.Run("Aliases", func(t *testing.T) {
+		assert.NoError(t, os.Setenv("ALIAS_URL", ""))
+		assert.NoError(t, os.Setenv("ALIAS_LEVEL", "debug"))
+		defer func() {
+			assert.NoError(t, os.Unsetenv("ALIAS_URL"))
+			assert.NoError(t, os.Unsetenv("ALIAS_LEVEL"))
+		}()
+
+		logger := new(testLogger)
+		fs := flag.NewFlagSet("app", flag.ContinueOnError)
+		args := []string{"-alias.http.port", "8080"}
+
+		c := new(aliasConfig)
+		err := Pick(c, WithFlagSet(fs, args), WithLogger(logger))
+		assert.NoError(t, err)
+
+		assert.Equal(t, &aliasConfig{
+			AliasURL:   "postgres://old",
+			AliasLevel: "debug",
+			AliasDebug: true,
+			AliasPort:  8080,
+		}, c)
+
+		assert.Equal(t, []string{
+			"WARNING: [AliasURL] env ALIAS_DB_URL is deprecated, use ALIAS_URL instead: ALIAS_DB_URL will be removed in v2",
+			"WARNING: [AliasDebug] env ALIAS_DEBUG is deprecated: use AliasLevel instead",
+			"WARNING: [AliasPort] flag -alias.http.port is deprecated, use -alias.port instead",
+		}, logger.msgs)
+	})
+
+	t.Run("Document", func(t *testing.T) {
+		docPath := filepath.Join(t.TempDir(), "config.yaml")
+		assert.NoError(t, os.WriteFile(docPath, []byte("alias_http_port: 9090\n"), 0644))
+
+		logger := new(testLogger)
+		c := new(aliasConfig)
+		err := Pick(c, SkipFlag(), SkipEnv(), FromFile(docPath), WithLogger(logger))
+		assert.NoError(t, err)
+
+		assert.Equal(t, 9090, c.AliasPort)
+		assert.Equal(t, []string{
+			"WARNING: [AliasPort] document ALIAS_HTTP_PORT is deprecated, use AliasPort instead",
+		}, logger.msgs)
+	})
+
+	t.Run("CanonicalFirst", func(t *testing.T) {
+		dir := t.TempDir()
+		urlPath := filepath.Join(dir, "url")
+		docPath := filepath.Join(dir, "config.yaml")
+		assert.NoError(t, os.WriteFile(urlPath, []byte("postgres://new"), 0644))
+		assert.NoError(t, os.WriteFile(docPath, []byte("alias_level: warn\n"), 0644))
+
+		assert.NoError(t, os.Setenv("ALIAS_URL_FILE", urlPath))
+		defer func() {
+			assert.NoError(t, os.Unsetenv("ALIAS_URL_FILE"))
+		}()
+
+		logger := new(testLogger)
+		c := new(aliasConfig)
+		err := Pick(c, SkipFlag(), FromFile(docPath), WithLogger(logger))
+		assert.NoError(t, err)
+
+		// The canonical names in the file environment variables and the document take precedence over the aliases in the environment variables
+		assert.Equal(t, "postgres://new", c.AliasURL)
+		assert.Equal(t, "warn", c.AliasLevel)
+		assert.Equal(t, []string{
+			"WARNING: [AliasURL] file ALIAS_URL_FILE is deprecated: ALIAS_DB_URL will be removed in v2",
+			"WARNING: [AliasDebug] env ALIAS_DEBUG is deprecated: use AliasLevel instead",
+		}, logger.msgs)
+	})
+
+	t.Run("Strict", func(t *testing.T) {
+		c := new(aliasConfig)
+		err := Pick(c, SkipFlag(), PrefixEnv("ALIAS_"), Strict(), WithLogger(new(testLogger)))
+		assert.Error(t, err)
+
+		// Aliases are not prefixed and are known names
+		assert.EqualError(t, err, "1 error occurred:\n\t* unknown env ALIAS_DEBUG\n\n")
+	})
+}
@@ -3,11 +3,11 @@
 package config
 
 import (
-	"os"
-	"path/filepath"
+	"context"
+	"fmt"
 	"sync"
 
-	"github.com/fsnotify/fsnotify"
+	multierror "github.com/hashicorp/go-multierror"
 )
 
 const (
@@ -16,6 +16,18 @@ const (
 	tagEnv     = "env"
 	tagFileEnv = "fileenv"
 	tagSep     = "sep"
+	tagLayout  = "layout"
+	tagDefault = "default"
+
+	tagRequired   = "required"
+	tagMin        = "min"
+	tagMax        = "max"
+	tagOneOf      = "oneof"
+	tagPattern    = "pattern"
+	tagSecret     = "secret"
+	tagDesc       = "desc"
+	tagAliases    = "aliases"
+	tagDeprecated = "deprecated"
 
 	envDebug            = "CONFIG_DEBUG"
 	envListSep          = "CONFIG_LIST_SEP"
@@ -26,20 +38,60 @@ const (
 	envPrefixEnv        = "CONFIG_PREFIX_ENV"
 	envPrefixFileEnv    = "CONFIG_PREFIX_FILE_ENV"
 	envTelepresence     = "CONFIG_TELEPRESENCE"
+	envFromFile         = "CONFIG_FROM_FILE"
+	envLenient          = "CONFIG_LENIENT"
+	envWatchDir         = "CONFIG_WATCH_DIR"
+	envPollInterval     = "CONFIG_POLL_INTERVAL"
+	envResolveSecrets   = "CONFIG_RESOLVE_SECRETS"
+	envEnvFile          = "CONFIG_ENV_FILE"
+	envExpandEnv        = "CONFIG_EXPAND_ENV"
+	envProfile          = "CONFIG_PROFILE"
+	envStrict           = "CONFIG_STRICT"
 	envTelepresenceRoot = "TELEPRESENCE_ROOT"
 
+	sourceFlag     = "flag"
+	sourceEnv      = "env"
+	sourceFile     = "file"
+	sourceDocument = "document"
+	sourceDefault  = "default"
+
 	line = "----------------------------------------------------------------------------------------------------"
 )
 
 // Update represents a configuration field that received a new value.
+// The value of a field holding a secret (see the secret tag) is masked.
 type Update struct {
 	Name  string
 	Value interface{}
 }
 
-// Pick reads values for exported fields of a struct from either command-line flags, environment variables, or configuration files.
-// Default values can also be specified.
+// FieldError is the error for a value that cannot be set on a field.
+type FieldError struct {
+	// Field is the name of the field.
+	Field string
+	// Source is where the value is read from (flag, env, file, document, or default).
+	Source string
+	// Value is the raw value read from the source (masked for fields holding secrets).
+	Value string
+	// Err is the error occurred when parsing the value.
+	Err error
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("invalid value %q for %s from %s: %s", e.Value, e.Field, e.Source, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// Pick reads values for exported fields of a struct from either command-line flags, environment variables, configuration files,
+// or a configuration document (see FromFile option).
+// Default values can also be specified either by setting them on the struct or using the default struct tag.
 // You should pass the pointer to a struct for config; otherwise you will get an error.
+// Once all values are read, fields are validated against their validation tags (required, min, max, oneof, and pattern).
+// If any value cannot be set on its field or fails validation, an error listing all such fields will be returned.
 func Pick(config interface{}, opts ...Option) error {
 	c := readerFromEnv()
 	for _, opt := range opts {
@@ -56,17 +108,89 @@ func Pick(config interface{}, opts ...Option) error {
 		return err
 	}
 
+	if err := c.checkValidators(config); err != nil {
+		c.log(1, err.Error())
+		return err
+	}
+
+	if err := c.loadEnvFiles(); err != nil {
+		c.log(1, err.Error())
+		return err
+	}
+
+	if err := c.loadDocument(); err != nil {
+		c.log(1, err.Error())
+		return err
+	}
+
 	c.registerFlags(v)
-	c.readFields(v)
+	if err := c.parseFlags(); err != nil {
+		c.log(1, err.Error())
+		return err
+	}
+
+	if err := c.checkHelp(v); err != nil {
+		return err
+	}
+
+	err = c.readFields(v)
+	if serr := c.checkUnknown(v); serr != nil {
+		err = multierror.Append(err, serr)
+	}
+	if verr := c.validateFields(v); verr != nil {
+		err = multierror.Append(err, verr)
+	}
 
-	return nil
+	if err == nil {
+		if verr := c.runValidators(nil, config); verr != nil {
+			c.log(1, verr.Error())
+			err = multierror.Append(err, verr)
+		}
+	}
+
+	// The configuration is only printed if it is valid, otherwise the errors are returned
+	if err == nil {
+		c.checkPrintConfig(v)
+	}
+
+	return err
 }
 
 // Watch first reads values for exported fields of a struct from either command-line flags, environment variables, or configuration files.
 // It then watches any change to those fields that their values are read from configuration files and notifies subscribers on a channel.
+// A new value that cannot be set on its field or fails validation is rejected and never reaches the struct or subscribers.
+// Writes that do not change the content of a file are ignored.
+// Files can also be watched by their directories (see WatchDir option) or by polling them (see PollInterval option).
+// The returned function stops watching and closes the subscriber channels (see WatchContext).
 func Watch(config sync.Locker, subscribers []chan Update, opts ...Option) (func(), error) {
+	w, err := WatchContext(context.Background(), config, subscribers, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return w.Close, nil
+}
+
+// WatchContext is the same as Watch, but the returned watcher stops when the given context is cancelled.
+// Updates are delivered to each subscriber in order and without blocking the watcher or other subscribers.
+// Once the watcher is fully stopped, all subscriber channels are closed.
+func WatchContext(ctx context.Context, config sync.Locker, subscribers []chan Update, opts ...Option) (*Watcher, error) {
+	w, err := prepareWatch(config, config, subscribers, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := w.start(ctx); err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
+
+// prepareWatch reads values for the given struct and returns a watcher (not started yet) for setting new values on the struct while holding the given lock.
+func prepareWatch(config interface{}, lock sync.Locker, subscribers []chan Update, opts ...Option) (*Watcher, error) {
 	c := readerFromEnv()
-	c.subscribers = subscribers
+	c.subscribers = newSubscribers(subscribers)
 	for _, opt := range opts {
 		opt(c)
 	}
@@ -81,84 +205,60 @@ func Watch(config sync.Locker, subscribers []chan Update, opts ...Option) (func(
 		return nil, err
 	}
 
+	if err := c.checkValidators(config); err != nil {
+		c.log(1, err.Error())
+		return nil, err
+	}
+
+	// A copy of the struct before any value is read for reloading all values later (see Watcher.Reload)
+	initial := snapshot(config)
+
+	if err := c.loadEnvFiles(); err != nil {
+		c.log(1, err.Error())
+		return nil, err
+	}
+
+	if err := c.loadDocument(); err != nil {
+		c.log(1, err.Error())
+		return nil, err
+	}
+
 	c.registerFlags(v)
-	c.readFields(v)
+	if err := c.parseFlags(); err != nil {
+		c.log(1, err.Error())
+		return nil, err
+	}
 
-	watcher, err := fsnotify.NewWatcher()
-	if err != nil {
-		c.log(1, "cannot create a watcher: %s", err)
+	if err := c.checkHelp(v); err != nil {
 		return nil, err
 	}
 
-	go func() {
-		for {
-			select {
-			case event, ok := <-watcher.Events:
-				if !ok {
-					break
-				}
-
-				path := filepath.Clean(event.Name)
-				c.log(6, "event received: %s %s", event.Op, event.Name)
-
-				// We only receive events for added files, this if check is redundant!
-				if f, ok := c.filesToFields[path]; ok {
-					// Write
-					if event.Op&fsnotify.Write == fsnotify.Write {
-						if b, err := os.ReadFile(path); err == nil {
-							val := string(b)
-							c.log(3, "received an update from %s: %s", path, val)
-							config.Lock()
-							_, _ = c.setFieldValue(f, val)
-							config.Unlock()
-						}
-					}
-
-					// Remove
-					// Kubernetes injects new values from ConfigMaps and Secrets by removing the mounted files and recreating them.
-					// When a watched file is removed, the fsnotify package will remove it from the watcher too.
-					// This if block is a workaround for the aforementioned Kubernetes situation.
-					if event.Op&fsnotify.Remove == fsnotify.Remove {
-						// Check if the removed file is already recreated
-						if _, err := os.Stat(path); err == nil {
-							if b, err := os.ReadFile(path); err == nil {
-								val := string(b)
-								c.log(3, "received an update from %s: %s", path, val)
-								config.Lock()
-								_, _ = c.setFieldValue(f, val)
-								config.Unlock()
-							}
-
-							// Re-Add a watch for the file
-							if err := watcher.Add(path); err != nil {
-								c.log(1, "cannot watch file %s: %s", f, err)
-							}
-						}
-					}
-				}
-			case err, ok := <-watcher.Errors:
-				if !ok {
-					break
-				}
-				c.log(1, "error watching: %s", err)
-			}
-		}
-	}()
+	err = c.readFields(v)
+	if serr := c.checkUnknown(v); serr != nil {
+		err = multierror.Append(err, serr)
+	}
+	if verr := c.validateFields(v); verr != nil {
+		err = multierror.Append(err, verr)
+	}
 
-	for path := range c.filesToFields {
-		if err := watcher.Add(path); err != nil {
-			c.log(1, "cannot watch file %s: %s", path, err)
-			return nil, err
+	if err == nil {
+		if verr := c.runValidators(nil, config); verr != nil {
+			c.log(1, verr.Error())
+			err = multierror.Append(err, verr)
 		}
 	}
 
-	close := func() {
-		_ = watcher.Close()
-		// TODO: closing subscriber channels causes data race if notifySubscribers is writing to any
-		/* for _, sub := range c.subscribers {
-			close(sub)
-		} */
+	// The configuration is only printed if it is valid, otherwise the errors are returned
+	if err == nil {
+		c.checkPrintConfig(v)
 	}
 
-	return close, nil
+	if err != nil {
+		return nil, err
+	}
+
+	w := newWatcher(c, config, lock)
+	w.initial = initial
+
+	return w, nil
 }
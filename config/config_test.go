@@ -3,14 +3,17 @@ package config
 import (
 	"errors"
 	"flag"
+	"io"
 	"net/url"
 	"os"
 	"reflect"
 	"regexp"
+	"strconv"
 	"sync"
 	"testing"
 	"time"
 
+	multierror "github.com/hashicorp/go-multierror"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/gardenbed/basil/ptr"
@@ -19,60 +22,62 @@ import (
 type config struct {
 	sync.Mutex
 	unexported      string
-	SkipFlag        string          `flag:"-"`
-	SkipFlagEnv     string          `flag:"-" env:"-"`
-	SkipFlagEnvFile string          `flag:"-" env:"-" fileenv:"-"`
-	String          string          // `flag:"string" env:"STRING" fileenv:"STRING_FILE"`
-	Bool            bool            // `flag:"bool" env:"BOOL" fileenv:"BOOL_FILE"`
-	Float32         float32         // `flag:"float32" env:"FLOAT32" fileenv:"FLOAT32_FILE"`
-	Float64         float64         // `flag:"float64" env:"FLOAT64" fileenv:"FLOAT64_FILE"`
-	Int             int             // `flag:"int" env:"INT" fileenv:"INT_FILE"`
-	Int8            int8            // `flag:"int8" env:"INT8" fileenv:"INT8_FILE"`
-	Int16           int16           // `flag:"int16" env:"INT16" fileenv:"INT16_FILE"`
-	Int32           int32           // `flag:"int32" env:"INT32" fileenv:"INT32_FILE"`
-	Int64           int64           // `flag:"int64" env:"INT64" fileenv:"INT64_FILE"`
-	Uint            uint            // `flag:"uint" env:"UINT" fileenv:"UINT_FILE"`
-	Uint8           uint8           // `flag:"uint8" env:"UINT8" fileenv:"UINT8_FILE"`
-	Uint16          uint16          // `flag:"uint16" env:"UINT16" fileenv:"UINT16_FILE"`
-	Uint32          uint32          // `flag:"uint32" env:"UINT32" fileenv:"UINT32_FILE"`
-	Uint64          uint64          // `flag:"uint64" env:"UINT64" fileenv:"UINT64_FILE"`
-	URL             url.URL         // `flag:"url" env:"URL" fileenv:"URL_FILE"`
-	Regexp          regexp.Regexp   // `flag:"regexp" env:"REGEXP" fileenv:"REGEXP_FILE"`
-	Duration        time.Duration   // `flag:"duration" env:"DURATION" fileenv:"DURATION_FILE"`
-	StringPointer   *string         // `flag:"string.pointer" env:"STRING_POINTER" fileenv:"STRING_POINTER_FILE"`
-	BoolPointer     *bool           // `flag:"bool.pointer" env:"BOOL_POINTER" fileenv:"BOOL_POINTER_FILE"`
-	Float32Pointer  *float32        // `flag:"float32.pointer" env:"FLOAT32_POINTER" fileenv:"FLOAT32_POINTER_FILE"`
-	Float64Pointer  *float64        // `flag:"float64.pointer" env:"FLOAT64_POINTER" fileenv:"FLOAT64_POINTER_FILE"`
-	IntPointer      *int            // `flag:"int.pointer" env:"INT_POINTER" fileenv:"INT_POINTER_FILE"`
-	Int8Pointer     *int8           // `flag:"int8.pointer" env:"INT8_POINTER" fileenv:"INT8_POINTER_FILE"`
-	Int16Pointer    *int16          // `flag:"int16.pointer" env:"INT16_POINTER" fileenv:"INT16_POINTER_FILE"`
-	Int32Pointer    *int32          // `flag:"int32.pointer" env:"INT32_POINTER" fileenv:"INT32_POINTER_FILE"`
-	Int64Pointer    *int64          // `flag:"int64.pointer" env:"INT64_POINTER" fileenv:"INT64_POINTER_FILE"`
-	UintPointer     *uint           // `flag:"uint.pointer" env:"UINT_POINTER" fileenv:"UINT_POINTER_FILE"`
-	Uint8Pointer    *uint8          // `flag:"uint8.pointer" env:"UINT8_POINTER" fileenv:"UINT8_POINTER_FILE"`
-	Uint16Pointer   *uint16         // `flag:"uint16.pointer" env:"UINT16_POINTER" fileenv:"UINT16_POINTER_FILE"`
-	Uint32Pointer   *uint32         // `flag:"uint32.pointer" env:"UINT32_POINTER" fileenv:"UINT32_POINTER_FILE"`
-	Uint64Pointer   *uint64         // `flag:"uint64.pointer" env:"UINT64_POINTER" fileenv:"UINT64_POINTER_FILE"`
-	URLPointer      *url.URL        // `flag:"url.pointer" env:"URL_POINTER" fileenv:"URL_POINTER_FILE"`
-	RegexpPointer   *regexp.Regexp  // `flag:"regexp.pointer" env:"REGEXP_POINTER" fileenv:"REGEXP_POINTER_FILE"`
-	DurationPointer *time.Duration  // `flag:"duration.pointer" env:"DURATION_POINTER" fileenv:"DURATION_POINTER_FILE"`
-	StringSlice     []string        // `flag:"string.slice" env:"STRING_SLICE" fileenv:"STRING_SLICE_FILE" sep:","`
-	BoolSlice       []bool          // `flag:"bool.slice" env:"BOOL_SLICE" fileenv:"BOOL_SLICE_FILE" sep:","`
-	Float32Slice    []float32       // `flag:"float32.slice" env:"FLOAT32_SLICE" fileenv:"FLOAT32_SLICE_FILE" sep:","`
-	Float64Slice    []float64       // `flag:"float64.slice" env:"FLOAT64_SLICE" fileenv:"FLOAT64_SLICE_FILE" sep:","`
-	IntSlice        []int           // `flag:"int.slice" env:"INT_SLICE" fileenv:"INT_SLICE_FILE" sep:","`
-	Int8Slice       []int8          // `flag:"int8.slice" env:"INT8_SLICE" fileenv:"INT8_SLICE_FILE" sep:","`
-	Int16Slice      []int16         // `flag:"int16.slice" env:"INT16_SLICE" fileenv:"INT16_SLICE_FILE" sep:","`
-	Int32Slice      []int32         // `flag:"int32.slice" env:"INT32_SLICE" fileenv:"INT32_SLICE_FILE" sep:","`
-	Int64Slice      []int64         // `flag:"int64.slice" env:"INT64_SLICE" fileenv:"INT64_SLICE_FILE" sep:","`
-	UintSlice       []uint          // `flag:"uint.slice" env:"UINT_SLICE" fileenv:"UINT_SLICE_FILE" sep:","`
-	Uint8Slice      []uint8         // `flag:"uint8.slice" env:"UINT8_SLICE" fileenv:"UINT8_SLICE_FILE" sep:","`
-	Uint16Slice     []uint16        // `flag:"uint16.slice" env:"UINT16_SLICE" fileenv:"UINT16_SLICE_FILE" sep:","`
-	Uint32Slice     []uint32        // `flag:"uint32.slice" env:"UINT32_SLICE" fileenv:"UINT32_SLICE_FILE" sep:","`
-	Uint64Slice     []uint64        // `flag:"uint64.slice" env:"UINT64_SLICE" fileenv:"UINT64_SLICE_FILE" sep:","`
-	URLSlice        []url.URL       // `flag:"url.slice" env:"URL_SLICE" fileenv:"URL_SLICE_FILE" sep:","`
-	RegexpSlice     []regexp.Regexp // `flag:"regexp.slice" env:"REGEXP_SLICE" fileenv:"REGEXP_SLICE_FILE" sep:","`
-	DurationSlice   []time.Duration // `flag:"duration.slice" env:"DURATION_SLICE" fileenv:"DURATION_SLICE_FILE" sep:","`
+	SkipFlag        string            `flag:"-"`
+	SkipFlagEnv     string            `flag:"-" env:"-"`
+	SkipFlagEnvFile string            `flag:"-" env:"-" fileenv:"-"`
+	String          string            // `flag:"string" env:"STRING" fileenv:"STRING_FILE"`
+	Bool            bool              // `flag:"bool" env:"BOOL" fileenv:"BOOL_FILE"`
+	Float32         float32           // `flag:"float32" env:"FLOAT32" fileenv:"FLOAT32_FILE"`
+	Float64         float64           // `flag:"float64" env:"FLOAT64" fileenv:"FLOAT64_FILE"`
+	Int             int               // `flag:"int" env:"INT" fileenv:"INT_FILE"`
+	Int8            int8              // `flag:"int8" env:"INT8" fileenv:"INT8_FILE"`
+	Int16           int16             // `flag:"int16" env:"INT16" fileenv:"INT16_FILE"`
+	Int32           int32             // `flag:"int32" env:"INT32" fileenv:"INT32_FILE"`
+	Int64           int64             // `flag:"int64" env:"INT64" fileenv:"INT64_FILE"`
+	Uint            uint              // `flag:"uint" env:"UINT" fileenv:"UINT_FILE"`
+	Uint8           uint8             // `flag:"uint8" env:"UINT8" fileenv:"UINT8_FILE"`
+	Uint16          uint16            // `flag:"uint16" env:"UINT16" fileenv:"UINT16_FILE"`
+	Uint32          uint32            // `flag:"uint32" env:"UINT32" fileenv:"UINT32_FILE"`
+	Uint64          uint64            // `flag:"uint64" env:"UINT64" fileenv:"UINT64_FILE"`
+	URL             url.URL           // `flag:"url" env:"URL" fileenv:"URL_FILE"`
+	Regexp          regexp.Regexp     // `flag:"regexp" env:"REGEXP" fileenv:"REGEXP_FILE"`
+	Duration        time.Duration     // `flag:"duration" env:"DURATION" fileenv:"DURATION_FILE"`
+	Time            time.Time         // `flag:"time" env:"TIME" fileenv:"TIME_FILE"`
+	StringMap       map[string]string // `flag:"string.map" env:"STRING_MAP" fileenv:"STRING_MAP_FILE"`
+	StringPointer   *string           // `flag:"string.pointer" env:"STRING_POINTER" fileenv:"STRING_POINTER_FILE"`
+	BoolPointer     *bool             // `flag:"bool.pointer" env:"BOOL_POINTER" fileenv:"BOOL_POINTER_FILE"`
+	Float32Pointer  *float32          // `flag:"float32.pointer" env:"FLOAT32_POINTER" fileenv:"FLOAT32_POINTER_FILE"`
+	Float64Pointer  *float64          // `flag:"float64.pointer" env:"FLOAT64_POINTER" fileenv:"FLOAT64_POINTER_FILE"`
+	IntPointer      *int              // `flag:"int.pointer" env:"INT_POINTER" fileenv:"INT_POINTER_FILE"`
+	Int8Pointer     *int8             // `flag:"int8.pointer" env:"INT8_POINTER" fileenv:"INT8_POINTER_FILE"`
+	Int16Pointer    *int16            // `flag:"int16.pointer" env:"INT16_POINTER" fileenv:"INT16_POINTER_FILE"`
+	Int32Pointer    *int32            // `flag:"int32.pointer" env:"INT32_POINTER" fileenv:"INT32_POINTER_FILE"`
+	Int64Pointer    *int64            // `flag:"int64.pointer" env:"INT64_POINTER" fileenv:"INT64_POINTER_FILE"`
+	UintPointer     *uint             // `flag:"uint.pointer" env:"UINT_POINTER" fileenv:"UINT_POINTER_FILE"`
+	Uint8Pointer    *uint8            // `flag:"uint8.pointer" env:"UINT8_POINTER" fileenv:"UINT8_POINTER_FILE"`
+	Uint16Pointer   *uint16           // `flag:"uint16.pointer" env:"UINT16_POINTER" fileenv:"UINT16_POINTER_FILE"`
+	Uint32Pointer   *uint32           // `flag:"uint32.pointer" env:"UINT32_POINTER" fileenv:"UINT32_POINTER_FILE"`
+	Uint64Pointer   *uint64           // `flag:"uint64.pointer" env:"UINT64_POINTER" fileenv:"UINT64_POINTER_FILE"`
+	URLPointer      *url.URL          // `flag:"url.pointer" env:"URL_POINTER" fileenv:"URL_POINTER_FILE"`
+	RegexpPointer   *regexp.Regexp    // `flag:"regexp.pointer" env:"REGEXP_POINTER" fileenv:"REGEXP_POINTER_FILE"`
+	DurationPointer *time.Duration    // `flag:"duration.pointer" env:"DURATION_POINTER" fileenv:"DURATION_POINTER_FILE"`
+	StringSlice     []string          // `flag:"string.slice" env:"STRING_SLICE" fileenv:"STRING_SLICE_FILE" sep:","`
+	BoolSlice       []bool            // `flag:"bool.slice" env:"BOOL_SLICE" fileenv:"BOOL_SLICE_FILE" sep:","`
+	Float32Slice    []float32         // `flag:"float32.slice" env:"FLOAT32_SLICE" fileenv:"FLOAT32_SLICE_FILE" sep:","`
+	Float64Slice    []float64         // `flag:"float64.slice" env:"FLOAT64_SLICE" fileenv:"FLOAT64_SLICE_FILE" sep:","`
+	IntSlice        []int             // `flag:"int.slice" env:"INT_SLICE" fileenv:"INT_SLICE_FILE" sep:","`
+	Int8Slice       []int8            // `flag:"int8.slice" env:"INT8_SLICE" fileenv:"INT8_SLICE_FILE" sep:","`
+	Int16Slice      []int16           // `flag:"int16.slice" env:"INT16_SLICE" fileenv:"INT16_SLICE_FILE" sep:","`
+	Int32Slice      []int32           // `flag:"int32.slice" env:"INT32_SLICE" fileenv:"INT32_SLICE_FILE" sep:","`
+	Int64Slice      []int64           // `flag:"int64.slice" env:"INT64_SLICE" fileenv:"INT64_SLICE_FILE" sep:","`
+	UintSlice       []uint            // `flag:"uint.slice" env:"UINT_SLICE" fileenv:"UINT_SLICE_FILE" sep:","`
+	Uint8Slice      []uint8           // `flag:"uint8.slice" env:"UINT8_SLICE" fileenv:"UINT8_SLICE_FILE" sep:","`
+	Uint16Slice     []uint16          // `flag:"uint16.slice" env:"UINT16_SLICE" fileenv:"UINT16_SLICE_FILE" sep:","`
+	Uint32Slice     []uint32          // `flag:"uint32.slice" env:"UINT32_SLICE" fileenv:"UINT32_SLICE_FILE" sep:","`
+	Uint64Slice     []uint64          // `flag:"uint64.slice" env:"UINT64_SLICE" fileenv:"UINT64_SLICE_FILE" sep:","`
+	URLSlice        []url.URL         // `flag:"url.slice" env:"URL_SLICE" fileenv:"URL_SLICE_FILE" sep:","`
+	RegexpSlice     []regexp.Regexp   // `flag:"regexp.slice" env:"REGEXP_SLICE" fileenv:"REGEXP_SLICE_FILE" sep:","`
+	DurationSlice   []time.Duration   // `flag:"duration.slice" env:"DURATION_SLICE" fileenv:"DURATION_SLICE_FILE" sep:","`
 }
 
 func (c1 *config) Equal(c2 *config) bool {
@@ -97,6 +102,8 @@ func (c1 *config) Equal(c2 *config) bool {
 		c1.URL == c2.URL &&
 		reflect.DeepEqual(c1.Regexp, c2.Regexp) &&
 		c1.Duration == c2.Duration &&
+		c1.Time.Equal(c2.Time) &&
+		reflect.DeepEqual(c1.StringMap, c2.StringMap) &&
 		reflect.DeepEqual(c1.StringPointer, c2.StringPointer) &&
 		reflect.DeepEqual(c1.BoolPointer, c2.BoolPointer) &&
 		reflect.DeepEqual(c1.Float32Pointer, c2.Float32Pointer) &&
@@ -236,6 +243,58 @@ func TestPick(t *testing.T) {
 			expectedError:  errors.New("a non-pointer type is passed"),
 			expectedConfig: &config{},
 		},
+		{
+			name:           "InvalidDocument",
+			args:           []string{"app"},
+			envs:           []env{},
+			files:          []file{},
+			config:         &config{},
+			opts:           []Option{FromFile("config.ini")},
+			expectedError:  errors.New("unsupported document format: .ini"),
+			expectedConfig: &config{},
+		},
+		{
+			name: "InvalidValues",
+			args: []string{"app"},
+			envs: []env{
+				{"INT", "abc"},
+				{"UINT8", "256"},
+			},
+			files:  []file{},
+			config: &config{},
+			opts:   nil,
+			expectedError: multierror.Append(nil,
+				&FieldError{
+					Field:  "Int",
+					Source: "env",
+					Value:  "abc",
+					Err:    &strconv.NumError{Func: "ParseInt", Num: "abc", Err: strconv.ErrSyntax},
+				},
+				&FieldError{
+					Field:  "Uint8",
+					Source: "env",
+					Value:  "256",
+					Err:    &strconv.NumError{Func: "ParseUint", Num: "256", Err: strconv.ErrRange},
+				},
+			),
+			expectedConfig: &config{},
+		},
+		{
+			name: "InvalidValuesWithLenientOption",
+			args: []string{"app"},
+			envs: []env{
+				{"STRING", "foo"},
+				{"INT", "abc"},
+				{"UINT8", "256"},
+			},
+			files:         []file{},
+			config:        &config{},
+			opts:          []Option{Lenient()},
+			expectedError: nil,
+			expectedConfig: &config{
+				String: "foo",
+			},
+		},
 		{
 			name:           "Empty",
 			args:           []string{"app"},
@@ -810,6 +869,8 @@ func TestWatch(t *testing.T) {
 		{"URL_FILE", "service-1", "service-2"},
 		{"REGEXP_FILE", "[:digit:]", "[:alpha:]"},
 		{"DURATION_FILE", "1s", "1m"},
+		{"TIME_FILE", "2020-01-01T00:00:00Z", "2021-01-01T00:00:00Z"},
+		{"STRING_MAP_FILE", "env=dev", "env=prod"},
 		{"STRING_SLICE_FILE", "foo,bar", "bar,foo"},
 		{"BOOL_SLICE_FILE", "false,true", "true,false"},
 		{"FLOAT32_SLICE_FILE", "2.7182,3.1415", "3.1415,2.7182"},
@@ -847,6 +908,8 @@ func TestWatch(t *testing.T) {
 		URL:           *url1,
 		Regexp:        *re1,
 		Duration:      time.Second,
+		Time:          time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		StringMap:     map[string]string{"env": "dev"},
 		StringSlice:   []string{"foo", "bar"},
 		BoolSlice:     []bool{false, true},
 		Float32Slice:  []float32{2.7182, 3.1415},
@@ -884,6 +947,8 @@ func TestWatch(t *testing.T) {
 		URL:           *url2,
 		Regexp:        *re2,
 		Duration:      time.Minute,
+		Time:          time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+		StringMap:     map[string]string{"env": "prod"},
 		StringSlice:   []string{"bar", "foo"},
 		BoolSlice:     []bool{true, false},
 		Float32Slice:  []float32{3.1415, 2.7182},
@@ -921,6 +986,8 @@ func TestWatch(t *testing.T) {
 		{"URL", *url1},
 		{"Regexp", *re1},
 		{"Duration", time.Second},
+		{"Time", time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"StringMap", map[string]string{"env": "dev"}},
 		{"StringSlice", []string{"foo", "bar"}},
 		{"BoolSlice", []bool{false, true}},
 		{"Float32Slice", []float32{2.7182, 3.1415}},
@@ -956,6 +1023,8 @@ func TestWatch(t *testing.T) {
 		{"URL", *url2},
 		{"Regexp", *re2},
 		{"Duration", time.Minute},
+		{"Time", time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"StringMap", map[string]string{"env": "prod"}},
 		{"StringSlice", []string{"bar", "foo"}},
 		{"BoolSlice", []bool{true, false}},
 		{"Float32Slice", []float32{3.1415, 2.7182}},
@@ -1127,3 +1196,241 @@ func TestWatch(t *testing.T) {
 	// flag.Parse() can be called only once
 	flag.Parse()
 }
+
+func TestPickValidation(t *testing.T) {
+	type validated struct {
+		Name     string `required:"true"`
+		Port     uint16 `min:"1024"`
+		LogLevel string `oneof:"debug|info|warn|error"`
+	}
+
+	tests := []struct {
+		name           string
+		envs           map[string]string
+		expectedError  error
+		expectedConfig *validated
+	}{
+		{
+			name: "Valid",
+			envs: map[string]string{
+				"NAME":      "app",
+				"PORT":      "8080",
+				"LOG_LEVEL": "info",
+			},
+			expectedError: nil,
+			expectedConfig: &validated{
+				Name:     "app",
+				Port:     8080,
+				LogLevel: "info",
+			},
+		},
+		{
+			name: "Invalid",
+			envs: map[string]string{
+				"PORT":      "80",
+				"LOG_LEVEL": "trace",
+			},
+			expectedError: multierror.Append(nil,
+				&ValidationError{Field: "Name", Tag: "required", Err: errors.New("value is required")},
+				&ValidationError{Field: "Port", Tag: "min", Err: errors.New("value must be at least 1024")},
+				&ValidationError{Field: "LogLevel", Tag: "oneof", Err: errors.New(`"trace" is not one of debug, info, warn, error`)},
+			),
+			expectedConfig: &validated{
+				Port:     80,
+				LogLevel: "trace",
+			},
+		},
+		{
+			name: "ZeroValue",
+			envs: map[string]string{
+				"NAME": "app",
+				"PORT": "0",
+			},
+			expectedError: multierror.Append(nil,
+				&ValidationError{Field: "Port", Tag: "min", Err: errors.New("value must be at least 1024")},
+			),
+			expectedConfig: &validated{
+				Name: "app",
+			},
+		},
+		{
+			name: "InvalidValueAndValidation",
+			envs: map[string]string{
+				"NAME": "app",
+				"PORT": "port",
+			},
+			expectedError: multierror.Append(nil,
+				&FieldError{
+					Field:  "Port",
+					Source: "env",
+					Value:  "port",
+					Err:    &strconv.NumError{Func: "ParseUint", Num: "port", Err: strconv.ErrSyntax},
+				},
+			),
+			expectedConfig: &validated{
+				Name: "app",
+			},
+		},
+	}
+
+	origArgs := os.Args
+	defer func() {
+		os.Args = origArgs
+	}()
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			os.Args = []string{"app"}
+
+			for name, value := range tc.envs {
+				err := os.Setenv(name, value)
+				assert.NoError(t, err)
+
+				defer func() {
+					assert.NoError(t, os.Unsetenv(name))
+				}()
+			}
+
+			c := new(validated)
+			err := Pick(c)
+
+			assert.Equal(t, tc.expectedError, err)
+			assert.Equal(t, tc.expectedConfig, c)
+		})
+	}
+}
+
+func TestWatchValidation(t *testing.T) {
+	type validated struct {
+		sync.Mutex
+		Port uint16 `min:"1024"`
+	}
+
+	tmpfile, err := os.CreateTemp("", "gotest_")
+	assert.NoError(t, err)
+
+	defer func() {
+		assert.NoError(t, os.Remove(tmpfile.Name()))
+	}()
+
+	_, err = tmpfile.WriteString("8080")
+	assert.NoError(t, err)
+
+	err = tmpfile.Close()
+	assert.NoError(t, err)
+
+	err = os.Setenv("PORT_FILE", tmpfile.Name())
+	assert.NoError(t, err)
+
+	defer func() {
+		assert.NoError(t, os.Unsetenv("PORT_FILE"))
+	}()
+
+	origArgs := os.Args
+	defer func() {
+		os.Args = origArgs
+	}()
+
+	os.Args = []string{"app"}
+
+	c := new(validated)
+	sub := make(chan Update, 10)
+	close, err := Watch(c, []chan Update{sub})
+	assert.NoError(t, err)
+	defer close()
+
+	c.Lock()
+	assert.Equal(t, uint16(8080), c.Port)
+	c.Unlock()
+	assert.Equal(t, Update{"Port", uint16(8080)}, <-sub)
+
+	// An invalid value should be rejected
+	err = os.WriteFile(tmpfile.Name(), []byte("80"), 0644)
+	assert.NoError(t, err)
+	time.Sleep(100 * time.Millisecond)
+
+	c.Lock()
+	assert.Equal(t, uint16(8080), c.Port)
+	c.Unlock()
+	assert.Len(t, sub, 0)
+
+	// A valid value should be accepted
+	err = os.WriteFile(tmpfile.Name(), []byte("9090"), 0644)
+	assert.NoError(t, err)
+	time.Sleep(100 * time.Millisecond)
+
+	c.Lock()
+	assert.Equal(t, uint16(9090), c.Port)
+	c.Unlock()
+	assert.Equal(t, Update{"Port", uint16(9090)}, <-sub)
+}
+
+func TestPickWithFlagSet(t *testing.T) {
+	type flagged struct {
+		ServerPort    uint16
+		ServerPortal  string
+		ServerEnabled bool
+		ServerTimeout time.Duration `default:"30s"`
+	}
+
+	tests := []struct {
+		name           string
+		args           []string
+		expectedError  string
+		expectedConfig *flagged
+	}{
+		{
+			name: "Valid",
+			args: []string{"--server.portal=admin", "-server.enabled", "--server.port", "8080"},
+			expectedConfig: &flagged{
+				ServerPort:    8080,
+				ServerPortal:  "admin",
+				ServerEnabled: true,
+				ServerTimeout: 30 * time.Second,
+			},
+		},
+		{
+			name: "SimilarFlagNames",
+			args: []string{"--server.portal=admin"},
+			expectedConfig: &flagged{
+				ServerPortal:  "admin",
+				ServerTimeout: 30 * time.Second,
+			},
+		},
+		{
+			name:           "InvalidValue",
+			args:           []string{"--server.timeout=thirty"},
+			expectedError:  `invalid value "thirty" for flag -server.timeout: time: invalid duration "thirty"`,
+			expectedConfig: &flagged{},
+		},
+	}
+
+	origArgs := os.Args
+	defer func() {
+		os.Args = origArgs
+	}()
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Values should not be read from os.Args
+			os.Args = []string{"app", "--server.port=9090"}
+
+			fs := flag.NewFlagSet("app", flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+
+			c := new(flagged)
+			err := Pick(c, WithFlagSet(fs, tc.args))
+
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expectedError)
+			}
+
+			assert.Equal(t, tc.expectedConfig, c)
+
+			// The global flag set should be left untouched
+			assert.Nil(t, flag.Lookup("server.port"))
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package config
+
+import (
+	"encoding"
+	"flag"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+var (
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	flagValueType       = reflect.TypeOf((*flag.Value)(nil)).Elem()
+)
+
+// decoders is the registry of custom decoders for types.
+var decoders = struct {
+	sync.RWMutex
+	m map[reflect.Type]func(string) (interface{}, error)
+}{
+	m: map[reflect.Type]func(string) (interface{}, error){},
+}
+
+// RegisterDecoder registers a function for decoding string values into a given type.
+// Fields of the given type (as well as pointers to and slices of the given type) will be set using the decoder.
+// The value returned by the decoder should be assignable or convertible to the given type.
+// A registered decoder takes precedence over the built-in parsing for the type.
+func RegisterDecoder(t reflect.Type, decode func(string) (interface{}, error)) {
+	decoders.Lock()
+	defer decoders.Unlock()
+
+	decoders.m[t] = decode
+}
+
+func getDecoder(t reflect.Type) (func(string) (interface{}, error), bool) {
+	decoders.RLock()
+	defer decoders.RUnlock()
+
+	decode, ok := decoders.m[t]
+	return decode, ok
+}
+
+// isBuiltinType determines whether or not a type has a built-in parsing that should not be overridden by its methods.
+// For example, regexp.Regexp implements encoding.TextUnmarshaler using Perl syntax while POSIX syntax is expected.
+func isBuiltinType(t reflect.Type) bool {
+	return (t.PkgPath() == "net/url" && t.Name() == "URL") ||
+		(t.PkgPath() == "regexp" && t.Name() == "Regexp") ||
+		(t.PkgPath() == "time" && (t.Name() == "Time" || t.Name() == "Duration"))
+}
+
+// hasDecoder determines whether or not a type can be decoded using either
+//   - a registered decoder,
+//   - the encoding.TextUnmarshaler interface,
+//   - or the flag.Value interface.
+func hasDecoder(t reflect.Type) bool {
+	if _, ok := getDecoder(t); ok {
+		return true
+	}
+
+	if t.Kind() == reflect.Ptr || isBuiltinType(t) {
+		return false
+	}
+
+	pt := reflect.PointerTo(t)
+	return pt.Implements(textUnmarshalerType) || pt.Implements(flagValueType)
+}
+
+// decodeValue decodes a string value into a new value of a given type.
+// It should only be called for types that have a decoder (see hasDecoder).
+func decodeValue(t reflect.Type, val string) (reflect.Value, error) {
+	if decode, ok := getDecoder(t); ok {
+		res, err := decode(val)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		v := reflect.ValueOf(res)
+		switch {
+		case !v.IsValid():
+			return reflect.Value{}, fmt.Errorf("decoder for %s returned nil", t)
+		case v.Type().AssignableTo(t):
+			return v, nil
+		case v.Type().ConvertibleTo(t):
+			return v.Convert(t), nil
+		default:
+			return reflect.Value{}, fmt.Errorf("decoder for %s returned %s", t, v.Type())
+		}
+	}
+
+	p := reflect.New(t)
+
+	if u, ok := p.Interface().(encoding.TextUnmarshaler); ok {
+		if err := u.UnmarshalText([]byte(val)); err != nil {
+			return reflect.Value{}, err
+		}
+		return p.Elem(), nil
+	}
+
+	if fv, ok := p.Interface().(flag.Value); ok {
+		if err := fv.Set(val); err != nil {
+			return reflect.Value{}, err
+		}
+		return p.Elem(), nil
+	}
+
+	return reflect.Value{}, fmt.Errorf("no decoder for type: %s", t)
+}
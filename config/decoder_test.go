@@ -0,0 +1,223 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// byteSize implements the encoding.TextUnmarshaler interface.
+type byteSize uint64
+
+func (s *byteSize) UnmarshalText(text []byte) error {
+	str := string(text)
+	unit := uint64(1)
+
+	switch {
+	case strings.HasSuffix(str, "KiB"):
+		str, unit = strings.TrimSuffix(str, "KiB"), 1<<10
+	case strings.HasSuffix(str, "MiB"):
+		str, unit = strings.TrimSuffix(str, "MiB"), 1<<20
+	}
+
+	u, err := strconv.ParseUint(str, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid byte size: %s", text)
+	}
+
+	*s = byteSize(u * unit)
+	return nil
+}
+
+// logLevel implements the flag.Value interface.
+type logLevel int
+
+func (l *logLevel) String() string {
+	return strconv.Itoa(int(*l))
+}
+
+func (l *logLevel) Set(val string) error {
+	switch val {
+	case "debug":
+		*l = 0
+	case "info":
+		*l = 1
+	case "error":
+		*l = 2
+	default:
+		return fmt.Errorf("invalid log level: %s", val)
+	}
+	return nil
+}
+
+// endpoint has a registered decoder.
+type endpoint struct {
+	Host string
+	Port uint16
+}
+
+// port has a registered decoder returning a convertible type.
+type port uint16
+
+// restoreDecoders restores the registry of decoders once a test is finished.
+func restoreDecoders(t *testing.T) {
+	decoders.RLock()
+	m := maps.Clone(decoders.m)
+	decoders.RUnlock()
+
+	t.Cleanup(func() {
+		decoders.Lock()
+		decoders.m = m
+		decoders.Unlock()
+	})
+}
+
+// registerTestDecoders registers the decoders for endpoint and port until a test is finished.
+func registerTestDecoders(t *testing.T) {
+	restoreDecoders(t)
+
+	RegisterDecoder(reflect.TypeOf(endpoint{}), func(val string) (interface{}, error) {
+		host, p, err := net.SplitHostPort(val)
+		if err != nil {
+			return nil, err
+		}
+
+		u, err := strconv.ParseUint(p, 10, 16)
+		if err != nil {
+			return nil, err
+		}
+
+		return endpoint{Host: host, Port: uint16(u)}, nil
+	})
+
+	RegisterDecoder(reflect.TypeOf(port(0)), func(val string) (interface{}, error) {
+		u, err := strconv.ParseUint(val, 10, 16)
+		return uint16(u), err
+	})
+}
+
+func TestRegisterDecoder(t *testing.T) {
+	restoreDecoders(t)
+
+	type custom struct{}
+	typ := reflect.TypeOf(custom{})
+
+	_, ok := getDecoder(typ)
+	assert.False(t, ok)
+
+	RegisterDecoder(typ, func(string) (interface{}, error) {
+		return custom{}, nil
+	})
+
+	decode, ok := getDecoder(typ)
+	assert.True(t, ok)
+	assert.NotNil(t, decode)
+}
+
+func TestIsBuiltinType(t *testing.T) {
+	tests := []struct {
+		name     string
+		typ      reflect.Type
+		expected bool
+	}{
+		{"URL", reflect.TypeOf(url.URL{}), true},
+		{"Regexp", reflect.TypeOf(regexp.Regexp{}), true},
+		{"Duration", reflect.TypeOf(time.Duration(0)), true},
+		{"Time", reflect.TypeOf(time.Time{}), true},
+		{"IP", reflect.TypeOf(net.IP{}), false},
+		{"String", reflect.TypeOf(""), false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, isBuiltinType(tc.typ))
+		})
+	}
+}
+
+func TestHasDecoder(t *testing.T) {
+	registerTestDecoders(t)
+
+	tests := []struct {
+		name     string
+		typ      reflect.Type
+		expected bool
+	}{
+		{"String", reflect.TypeOf(""), false},
+		{"Struct", reflect.TypeOf(struct{}{}), false},
+		{"Regexp", reflect.TypeOf(regexp.Regexp{}), false},
+		{"Time", reflect.TypeOf(time.Time{}), false},
+		{"TextUnmarshaler", reflect.TypeOf(byteSize(0)), true},
+		{"TextUnmarshalerPointer", reflect.TypeOf(new(byteSize)), false},
+		{"FlagValue", reflect.TypeOf(logLevel(0)), true},
+		{"IP", reflect.TypeOf(net.IP{}), true},
+		{"Registered", reflect.TypeOf(endpoint{}), true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, hasDecoder(tc.typ))
+		})
+	}
+}
+
+func TestDecodeValue(t *testing.T) {
+	registerTestDecoders(t)
+
+	type unknown struct{}
+
+	RegisterDecoder(reflect.TypeOf(unknown{}), func(val string) (interface{}, error) {
+		switch val {
+		case "nil":
+			return nil, nil
+		case "error":
+			return nil, errors.New("decoder error")
+		default:
+			return val, nil
+		}
+	})
+
+	tests := []struct {
+		name          string
+		typ           reflect.Type
+		val           string
+		expectedError string
+		expectedValue interface{}
+	}{
+		{"TextUnmarshaler", reflect.TypeOf(byteSize(0)), "512MiB", "", byteSize(512 << 20)},
+		{"TextUnmarshalerError", reflect.TypeOf(byteSize(0)), "512MB", "invalid byte size: 512MB", nil},
+		{"FlagValue", reflect.TypeOf(logLevel(0)), "error", "", logLevel(2)},
+		{"FlagValueError", reflect.TypeOf(logLevel(0)), "trace", "invalid log level: trace", nil},
+		{"IP", reflect.TypeOf(net.IP{}), "10.0.0.1", "", net.ParseIP("10.0.0.1")},
+		{"Registered", reflect.TypeOf(endpoint{}), "localhost:8080", "", endpoint{"localhost", 8080}},
+		{"RegisteredError", reflect.TypeOf(endpoint{}), "localhost", "address localhost: missing port in address", nil},
+		{"RegisteredConvertible", reflect.TypeOf(port(0)), "8080", "", port(8080)},
+		{"RegisteredNil", reflect.TypeOf(unknown{}), "nil", "decoder for config.unknown returned nil", nil},
+		{"RegisteredDecoderError", reflect.TypeOf(unknown{}), "error", "decoder error", nil},
+		{"RegisteredWrongType", reflect.TypeOf(unknown{}), "value", "decoder for config.unknown returned string", nil},
+		{"NoDecoder", reflect.TypeOf(""), "value", "no decoder for type: string", nil},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			v, err := decodeValue(tc.typ, tc.val)
+
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedValue, v.Interface())
+			} else {
+				assert.EqualError(t, err, tc.expectedError)
+			}
+		})
+	}
+}
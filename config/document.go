@@ -0,0 +1,235 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	formatYAML = "yaml"
+	formatJSON = "json"
+	formatTOML = "toml"
+)
+
+// document is a structured configuration file (YAML, JSON, or TOML) loaded into memory.
+type document struct {
+	format string
+	data   map[string]interface{}
+}
+
+// getDocFormat determines the format of a configuration document from its file extension.
+func getDocFormat(path string) (string, error) {
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		return formatYAML, nil
+	case ".json":
+		return formatJSON, nil
+	case ".toml":
+		return formatTOML, nil
+	default:
+		return "", fmt.Errorf("unsupported document format: %s", ext)
+	}
+}
+
+// readDocument reads and parses a configuration document.
+func readDocument(path string) (*document, error) {
+	format, err := getDocFormat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := parseDocument(format, b)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse %s: %s", path, err)
+	}
+
+	return doc, nil
+}
+
+// parseDocument parses a configuration document in the given format.
+func parseDocument(format string, b []byte) (*document, error) {
+	var err error
+	data := map[string]interface{}{}
+
+	switch format {
+	case formatYAML:
+		err = yaml.Unmarshal(b, &data)
+	case formatJSON:
+		dec := json.NewDecoder(bytes.NewReader(b))
+		dec.UseNumber()
+		err = dec.Decode(&data)
+	case formatTOML:
+		err = toml.Unmarshal(b, &data)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &document{
+		format: format,
+		data:   data,
+	}, nil
+}
+
+// getDocKey returns the key for reading the value of a field from a configuration document.
+// The key can be customized using the struct tag matching the document format (`yaml`, `json`, or `toml`).
+// If the document format is not known, the tags are checked in that order.
+func getDocKey(f reflect.StructField, format string) string {
+	tags := []string{formatYAML, formatJSON, formatTOML}
+	if format != "" {
+		tags = []string{format}
+	}
+
+	for _, tag := range tags {
+		// Drop options such as omitempty
+		if name, _, _ := strings.Cut(f.Tag.Get(tag), ","); name != "" {
+			return name
+		}
+	}
+
+	return f.Name
+}
+
+// normalizeKey makes document keys comparable regardless of their casing and word separators.
+//
+//	LogLevel   -->  loglevel
+//	log_level  -->  loglevel
+//	log-level  -->  loglevel
+func normalizeKey(key string) string {
+	key = strings.ToLower(key)
+	key = strings.NewReplacer("_", "", "-", "", ".", "").Replace(key)
+
+	return key
+}
+
+// lookupKey returns the value of a key in a map decoded from a document.
+// A key that does not exactly exist is matched against normalized keys.
+func lookupKey(m map[string]interface{}, key string) (interface{}, bool) {
+	if val, ok := m[key]; ok {
+		return val, true
+	}
+
+	for k, val := range m {
+		if normalizeKey(k) == normalizeKey(key) {
+			return val, true
+		}
+	}
+
+	return nil, false
+}
+
+// lookup returns the string value of a key in the document.
+// Keys of nested fields are looked up in nested tables (maps) of the document.
+// The elements of lists and the entries of tables are joined using the given list separator.
+// Dates and times are formatted using the given layout.
+func (d *document) lookup(keys []string, listSep, layout string) (string, bool) {
+	var val interface{} = d.data
+
+	for _, key := range keys {
+		var ok bool
+		if val, ok = lookupNode(val, key); !ok {
+			return "", false
+		}
+	}
+
+	if val == nil {
+		return "", false
+	}
+
+	return stringifyDocValue(val, listSep, layout), true
+}
+
+// lookupNode returns the value of a key in a table (map) or the element at an index in a list decoded from a document.
+func lookupNode(node interface{}, key string) (interface{}, bool) {
+	switch n := node.(type) {
+	case map[string]interface{}:
+		return lookupKey(n, key)
+	case []interface{}:
+		if i, err := strconv.Atoi(key); err == nil && i >= 0 && i < len(n) {
+			return n[i], true
+		}
+	case []map[string]interface{}:
+		if i, err := strconv.Atoi(key); err == nil && i >= 0 && i < len(n) {
+			return n[i], true
+		}
+	}
+
+	return nil, false
+}
+
+// keys returns the keys of a table (map) or the indices of a list at the given keys in the document.
+func (d *document) keys(keys []string) []string {
+	var val interface{} = d.data
+
+	for _, key := range keys {
+		var ok bool
+		if val, ok = lookupNode(val, key); !ok {
+			return nil
+		}
+	}
+
+	var res []string
+
+	switch n := val.(type) {
+	case map[string]interface{}:
+		for key := range n {
+			res = append(res, key)
+		}
+	case []interface{}:
+		for i := range n {
+			res = append(res, strconv.Itoa(i))
+		}
+	case []map[string]interface{}:
+		for i := range n {
+			res = append(res, strconv.Itoa(i))
+		}
+	}
+
+	return res
+}
+
+// stringifyDocValue converts a value decoded from a document to a string parsable by setFieldValue.
+func stringifyDocValue(val interface{}, listSep, layout string) string {
+	switch v := val.(type) {
+	case string:
+		return v
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case time.Time:
+		return v.Format(layout)
+	case []interface{}:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = stringifyDocValue(item, listSep, layout)
+		}
+		return strings.Join(items, listSep)
+	case map[string]interface{}:
+		entries := make([]string, 0, len(v))
+		for key, item := range v {
+			entries = append(entries, key+"="+stringifyDocValue(item, listSep, layout))
+		}
+		sort.Strings(entries)
+		return strings.Join(entries, listSep)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
@@ -0,0 +1,288 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetDocFormat(t *testing.T) {
+	tests := []struct {
+		path           string
+		expectedFormat string
+		expectedError  string
+	}{
+		{"config.yaml", "yaml", ""},
+		{"config.yml", "yaml", ""},
+		{"config.YAML", "yaml", ""},
+		{"config.json", "json", ""},
+		{"config.toml", "toml", ""},
+		{"config.ini", "", "unsupported document format: .ini"},
+		{"config", "", "unsupported document format: "},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.path, func(t *testing.T) {
+			format, err := getDocFormat(tc.path)
+
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedFormat, format)
+			} else {
+				assert.EqualError(t, err, tc.expectedError)
+			}
+		})
+	}
+}
+
+func TestReadDocument(t *testing.T) {
+	tests := []struct {
+		name             string
+		fileName         string
+		content          string
+		expectedError    string
+		expectedDocument *document
+	}{
+		{
+			name:          "UnsupportedFormat",
+			fileName:      "config.ini",
+			content:       "",
+			expectedError: "unsupported document format: .ini",
+		},
+		{
+			name:          "InvalidYAML",
+			fileName:      "config.yaml",
+			content:       "log_level: [",
+			expectedError: "cannot parse",
+		},
+		{
+			name:     "YAML",
+			fileName: "config.yaml",
+			content:  "log_level: debug\nport: 8080\nendpoints:\n  - url1\n  - url2\n",
+			expectedDocument: &document{
+				format: formatYAML,
+				data: map[string]interface{}{
+					"log_level": "debug",
+					"port":      8080,
+					"endpoints": []interface{}{"url1", "url2"},
+				},
+			},
+		},
+		{
+			name:     "JSON",
+			fileName: "config.json",
+			content:  `{ "logLevel": "debug", "port": 8080, "endpoints": ["url1", "url2"] }`,
+			expectedDocument: &document{
+				format: formatJSON,
+				data: map[string]interface{}{
+					"logLevel":  "debug",
+					"port":      json.Number("8080"),
+					"endpoints": []interface{}{"url1", "url2"},
+				},
+			},
+		},
+		{
+			name:     "TOML",
+			fileName: "config.toml",
+			content:  "log-level = \"debug\"\nport = 8080\nendpoints = [\"url1\", \"url2\"]\n",
+			expectedDocument: &document{
+				format: formatTOML,
+				data: map[string]interface{}{
+					"log-level": "debug",
+					"port":      int64(8080),
+					"endpoints": []interface{}{"url1", "url2"},
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tc.fileName)
+			err := os.WriteFile(path, []byte(tc.content), 0644)
+			assert.NoError(t, err)
+
+			doc, err := readDocument(path)
+
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedDocument, doc)
+			} else {
+				assert.ErrorContains(t, err, tc.expectedError)
+				assert.Nil(t, doc)
+			}
+		})
+	}
+}
+
+func TestGetDocKey(t *testing.T) {
+	type fields struct {
+		LogLevel string
+		Port     uint16 `yaml:"listen_port" json:"listenPort,omitempty" toml:"listen-port"`
+		Timeout  string `json:"timeout"`
+		Skipped  string `yaml:"-"`
+	}
+
+	tests := []struct {
+		name        string
+		field       string
+		format      string
+		expectedKey string
+	}{
+		{"NoTag", "LogLevel", formatYAML, "LogLevel"},
+		{"YAMLTag", "Port", formatYAML, "listen_port"},
+		{"JSONTag", "Port", formatJSON, "listenPort"},
+		{"TOMLTag", "Port", formatTOML, "listen-port"},
+		{"OtherFormatTag", "Timeout", formatYAML, "Timeout"},
+		{"UnknownFormat", "Timeout", "", "timeout"},
+		{"Skip", "Skipped", formatYAML, "-"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			f, ok := reflect.TypeOf(fields{}).FieldByName(tc.field)
+			assert.True(t, ok)
+
+			assert.Equal(t, tc.expectedKey, getDocKey(f, tc.format))
+		})
+	}
+}
+
+func TestNormalizeKey(t *testing.T) {
+	tests := []struct {
+		key         string
+		expectedKey string
+	}{
+		{"LogLevel", "loglevel"},
+		{"logLevel", "loglevel"},
+		{"log_level", "loglevel"},
+		{"log-level", "loglevel"},
+		{"log.level", "loglevel"},
+		{"LOG_LEVEL", "loglevel"},
+	}
+
+	for _, tc := range tests {
+		assert.Equal(t, tc.expectedKey, normalizeKey(tc.key))
+	}
+}
+
+func TestDocumentLookup(t *testing.T) {
+	d := &document{
+		format: formatYAML,
+		data: map[string]interface{}{
+			"log_level": "debug",
+			"Port":      8080,
+			"endpoints": []interface{}{"url1", "url2"},
+			"empty":     nil,
+			"database": map[string]interface{}{
+				"host": "localhost",
+				"connection_pool": map[string]interface{}{
+					"max-size": 10,
+				},
+			},
+			"upstreams": []interface{}{
+				map[string]interface{}{"host": "a.local"},
+				map[string]interface{}{"host": "b.local"},
+			},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		keys          []string
+		listSep       string
+		expectedValue string
+		expectedOK    bool
+	}{
+		{"ExactKey", []string{"log_level"}, ",", "debug", true},
+		{"NormalizedKey", []string{"LogLevel"}, ",", "debug", true},
+		{"Number", []string{"Port"}, ",", "8080", true},
+		{"List", []string{"Endpoints"}, "|", "url1|url2", true},
+		{"Null", []string{"Empty"}, ",", "", false},
+		{"Missing", []string{"Timeout"}, ",", "", false},
+		{"Nested", []string{"Database", "Host"}, ",", "localhost", true},
+		{"DeeplyNested", []string{"Database", "ConnectionPool", "MaxSize"}, ",", "10", true},
+		{"NestedMissing", []string{"Database", "Port"}, ",", "", false},
+		{"NotTable", []string{"LogLevel", "Value"}, ",", "", false},
+		{"ListElement", []string{"Upstreams", "1", "Host"}, ",", "b.local", true},
+		{"ListIndexOutOfRange", []string{"Upstreams", "2", "Host"}, ",", "", false},
+		{"ListInvalidIndex", []string{"Upstreams", "first", "Host"}, ",", "", false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			value, ok := d.lookup(tc.keys, tc.listSep, time.RFC3339)
+
+			assert.Equal(t, tc.expectedOK, ok)
+			assert.Equal(t, tc.expectedValue, value)
+		})
+	}
+}
+
+func TestDocumentKeys(t *testing.T) {
+	d := &document{
+		format: formatTOML,
+		data: map[string]interface{}{
+			"log_level": "debug",
+			"upstreams": []map[string]interface{}{
+				{"host": "a.local"},
+				{"host": "b.local"},
+			},
+			"backends": map[string]interface{}{
+				"primary":   map[string]interface{}{"host": "a.local"},
+				"secondary": map[string]interface{}{"host": "b.local"},
+			},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		keys         []string
+		expectedKeys []string
+	}{
+		{"List", []string{"Upstreams"}, []string{"0", "1"}},
+		{"Table", []string{"Backends"}, []string{"primary", "secondary"}},
+		{"Value", []string{"LogLevel"}, nil},
+		{"Missing", []string{"Timeout"}, nil},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.ElementsMatch(t, tc.expectedKeys, d.keys(tc.keys))
+		})
+	}
+}
+
+func TestStringifyDocValue(t *testing.T) {
+	tests := []struct {
+		name           string
+		value          interface{}
+		layout         string
+		expectedString string
+	}{
+		{"String", "content", time.RFC3339, "content"},
+		{"Bool", true, time.RFC3339, "true"},
+		{"Int", 9223372036854775807, time.RFC3339, "9223372036854775807"},
+		{"Int64", int64(-9223372036854775808), time.RFC3339, "-9223372036854775808"},
+		{"Uint64", uint64(18446744073709551615), time.RFC3339, "18446744073709551615"},
+		{"Float32", float32(3.1415), time.RFC3339, "3.1415"},
+		{"Float64", 3.14159265359, time.RFC3339, "3.14159265359"},
+		{"LargeFloat64", 1e21, time.RFC3339, "1000000000000000000000"},
+		{"JSONNumber", json.Number("2.7182"), time.RFC3339, "2.7182"},
+		{"Time", time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), time.RFC3339, "2020-01-01T00:00:00Z"},
+		{"TimeWithLayout", time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), time.DateOnly, "2020-01-01"},
+		{"List", []interface{}{"a", 1, true}, time.RFC3339, "a,1,true"},
+		{"Table", map[string]interface{}{"team": "backend", "env": "prod"}, time.RFC3339, "env=prod,team=backend"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedString, stringifyDocValue(tc.value, ",", tc.layout))
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// Dump renders the effective values of a config struct along with the source from which each value is read.
+// Values of fields holding secrets (see the secret tag) and values resolved from references to secrets are redacted.
+// Sources are determined by reading the values again, so the same options passed to Pick or Watch should be passed to Dump too.
+// Fields with no value from any source are reported with the default source.
+// If the struct implements sync.Locker, it is locked while its values are read.
+func Dump(config interface{}, opts ...Option) (string, error) {
+	c := readerFromEnv()
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	// Values are only read for determining their sources
+	c.debug = 0
+	c.subscribers = nil
+
+	v, err := validateStruct(config)
+	if err != nil {
+		return "", err
+	}
+
+	if err := c.loadEnvFiles(); err != nil {
+		return "", err
+	}
+
+	if err := c.loadDocument(); err != nil {
+		return "", err
+	}
+
+	if c.flagSet != nil && c.flagSet.Parsed() {
+		if err := c.parseFlags(); err != nil {
+			return "", err
+		}
+	}
+
+	if l, ok := config.(sync.Locker); ok {
+		l.Lock()
+		defer l.Unlock()
+	}
+
+	return c.dump(v)
+}
+
+// dump renders the current values of all fields of a struct along with their sources.
+func (r *reader) dump(v reflect.Value) (string, error) {
+	buf := new(bytes.Buffer)
+	tw := tabwriter.NewWriter(buf, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "FIELD\tVALUE\tSOURCE")
+
+	r.iterateOnCopy(v, func(f fieldInfo) {
+		raw, source, _ := r.getFieldValue(f)
+		if source == "" {
+			source = sourceDefault
+		}
+
+		val := formatValue(f.value, f.listSep, f.layout)
+		val = maskValue(f.secret || r.isReference(raw), val)
+
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", f.name, val, source)
+	})
+
+	if err := tw.Flush(); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// formatValue converts the value of a field to a string in the same format values are read.
+func formatValue(v reflect.Value, listSep, layout string) string {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+
+	if t, ok := v.Interface().(time.Time); ok {
+		return t.Format(layout)
+	}
+
+	// Use an addressable copy for methods with pointer receivers (i.e. url.URL)
+	p := reflect.New(v.Type())
+	p.Elem().Set(v)
+	if s, ok := p.Interface().(fmt.Stringer); ok {
+		return s.String()
+	}
+
+	switch v.Kind() {
+	case reflect.Slice:
+		items := make([]string, v.Len())
+		for i := range items {
+			items[i] = formatValue(v.Index(i), listSep, layout)
+		}
+		return strings.Join(items, listSep)
+
+	case reflect.Map:
+		entries := make([]string, 0, v.Len())
+		for _, key := range v.MapKeys() {
+			entries = append(entries, fmt.Sprintf("%v=%v", key.Interface(), v.MapIndex(key).Interface()))
+		}
+		sort.Strings(entries)
+		return strings.Join(entries, listSep)
+	}
+
+	return fmt.Sprintf("%v", v.Interface())
+}
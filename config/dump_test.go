@@ -0,0 +1,101 @@
+package config
+
+import (
+	"flag"
+	"net/url"
+	"os"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gardenbed/basil/ptr"
+)
+
+func TestFormatValue(t *testing.T) {
+	u, _ := url.Parse("http://localhost:8080")
+
+	tests := []struct {
+		name          string
+		value         interface{}
+		expectedValue string
+	}{
+		{"String", "content", "content"},
+		{"Int", 8080, "8080"},
+		{"Bool", true, "true"},
+		{"NilPointer", (*string)(nil), ""},
+		{"Pointer", ptr.String("content"), "content"},
+		{"Duration", time.Minute, "1m0s"},
+		{"Time", time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), "2020-01-01"},
+		{"URL", *u, "http://localhost:8080"},
+		{"URLPointer", u, "http://localhost:8080"},
+		{"Slice", []int{1, 2, 3}, "1,2,3"},
+		{"Map", map[string]string{"b": "2", "a": "1"}, "a=1,b=2"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			v := reflect.ValueOf(tc.value)
+			assert.Equal(t, tc.expectedValue, formatValue(v, ",", "2006-01-02"))
+		})
+	}
+}
+
+func TestDump(t *testing.T) {
+	type dumped struct {
+		sync.Mutex
+		DumpName     string `default:"app"`
+		DumpPort     int
+		DumpLevel    string
+		DumpPassword string
+		DumpTimeout  time.Duration
+		DumpHidden   string `secret:"true"`
+	}
+
+	assert.NoError(t, os.Setenv("DUMP_PORT", "8080"))
+	assert.NoError(t, os.Setenv("DUMP_PASSWORD", "s3cr3t"))
+	defer func() {
+		assert.NoError(t, os.Unsetenv("DUMP_PORT"))
+		assert.NoError(t, os.Unsetenv("DUMP_PASSWORD"))
+	}()
+
+	origArgs := os.Args
+	defer func() {
+		os.Args = origArgs
+	}()
+
+	os.Args = []string{"app"}
+
+	fs := flag.NewFlagSet("app", flag.ContinueOnError)
+
+	c := &dumped{
+		DumpTimeout: time.Minute,
+		DumpHidden:  "hidden",
+	}
+
+	assert.NoError(t, Pick(c, WithFlagSet(fs, []string{"-dump.level=debug"})))
+
+	t.Run("InvalidConfig", func(t *testing.T) {
+		out, err := Dump(new(int))
+		assert.EqualError(t, err, "a non-struct type is passed")
+		assert.Empty(t, out)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		out, err := Dump(c, WithFlagSet(fs, nil))
+		assert.NoError(t, err)
+
+		expected := "" +
+			"FIELD         VALUE  SOURCE\n" +
+			"DumpName      app    default\n" +
+			"DumpPort      8080   env\n" +
+			"DumpLevel     debug  flag\n" +
+			"DumpPassword  *****  env\n" +
+			"DumpTimeout   1m0s   default\n" +
+			"DumpHidden    *****  default\n"
+
+		assert.Equal(t, expected, out)
+	})
+}
@@ -0,0 +1,458 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// maxElems is the maximum number of elements read for a slice of structs.
+const maxElems = 1024
+
+// placeholderKey is the key of a single element standing for all elements of a slice or a map (i.e. Upstreams[*].Host).
+// In document keys, placeholderIndex is used for slices instead, since slices and maps have different schemas.
+const (
+	placeholderKey   = "*"
+	placeholderIndex = "[*]"
+)
+
+// getStructElem returns the struct type of elements if a type is a slice of structs or a map of strings to structs.
+// Elements can also be pointers to structs.
+func getStructElem(t reflect.Type) (reflect.Type, bool) {
+	if t.Kind() != reflect.Slice && (t.Kind() != reflect.Map || t.Key().Kind() != reflect.String) {
+		return nil, false
+	}
+
+	e := t.Elem()
+	if e.Kind() == reflect.Ptr {
+		e = e.Elem()
+	}
+
+	if e.Kind() != reflect.Struct || isTypeSupported(e) {
+		return nil, false
+	}
+
+	return e, true
+}
+
+// getElemInfo determines the names of an element in a slice or a map for every source.
+// The names of the element are used as prefixes for the fields of the element.
+//
+//	Upstreams[0]        -->  -upstreams.0        UPSTREAMS_0        upstreams.0
+//	Upstreams[primary]  -->  -upstreams.primary  UPSTREAMS_PRIMARY  upstreams.primary
+func getElemInfo(v reflect.Value, parent fieldInfo, key string) fieldInfo {
+	info := parent
+	info.value = v
+	info.name = fmt.Sprintf("%s[%s]", parent.name, key)
+
+	if parent.flagName != skip {
+		info.flagName = parent.flagName + "." + strings.ToLower(key)
+	}
+
+	if parent.envName != skip {
+		info.envName = parent.envName + "_" + strings.ToUpper(key)
+	}
+
+	if parent.fileEnvName != skip {
+		info.fileEnvName = parent.fileEnvName + "_" + strings.ToUpper(key)
+	}
+
+	if len(parent.docKeys) == 0 || parent.docKeys[0] != skip {
+		info.docKeys = append(append([]string{}, parent.docKeys...), key)
+	}
+
+	return info
+}
+
+// iterateOnElems iterates over the fields of all elements in a slice of structs or a map of strings to structs.
+// Elements are discovered from indexed (UPSTREAMS_0_HOST) or keyed (UPSTREAMS_PRIMARY_HOST) environment variables,
+// flags (-upstreams.0.host or -upstreams.primary.host), and the configuration document.
+// Elements already in the slice or map are always included.
+// If placeholders are enabled, a single empty element stands for all elements instead, so the fields only depend on the type of the struct.
+func (r *reader) iterateOnElems(v reflect.Value, f reflect.StructField, parent fieldInfo, visited []reflect.Type, handle func(f fieldInfo)) {
+	t := v.Type()
+	tElem, _ := getStructElem(t)
+	isPtr := t.Elem().Kind() == reflect.Ptr
+
+	info := r.getFieldInfo(v, f, parent, true)
+
+	if r.placeholders {
+		elem := reflect.New(tElem).Elem()
+		elemInfo := getElemInfo(elem, info, placeholderKey)
+		if t.Kind() == reflect.Slice && len(elemInfo.docKeys) > 0 && elemInfo.docKeys[0] != skip {
+			elemInfo.docKeys[len(elemInfo.docKeys)-1] = placeholderIndex
+		}
+
+		r.iterateOnNestedFields(elem, elemInfo, append(visited, tElem), handle)
+		return
+	}
+
+	keys := r.getElemKeys(v, info)
+	visited = append(visited, tElem)
+
+	if t.Kind() == reflect.Slice {
+		n := v.Len()
+		for _, key := range keys {
+			if i, _ := strconv.Atoi(key); i+1 > n {
+				n = i + 1
+			}
+		}
+
+		if n > v.Len() {
+			s := reflect.MakeSlice(t, n, n)
+			reflect.Copy(s, v)
+			v.Set(s)
+		}
+
+		for i := 0; i < v.Len(); i++ {
+			elem := v.Index(i)
+			if isPtr {
+				if elem.IsNil() {
+					elem.Set(reflect.New(tElem))
+				}
+				elem = elem.Elem()
+			}
+
+			r.iterateOnNestedFields(elem, getElemInfo(elem, info, strconv.Itoa(i)), visited, handle)
+		}
+
+		return
+	}
+
+	if v.IsNil() {
+		v.Set(reflect.MakeMap(t))
+	}
+
+	for _, key := range keys {
+		k := reflect.ValueOf(key).Convert(t.Key())
+		cur := v.MapIndex(k)
+
+		if isPtr {
+			if !cur.IsValid() || cur.IsNil() {
+				cur = reflect.New(tElem)
+				v.SetMapIndex(k, cur)
+			}
+
+			elem := cur.Elem()
+			r.iterateOnNestedFields(elem, getElemInfo(elem, info, key), visited, handle)
+			continue
+		}
+
+		// Map elements are not addressable, so fields are set on a copy and the copy is stored in the map
+		elem := reflect.New(tElem).Elem()
+		if cur.IsValid() {
+			elem.Set(cur)
+		}
+
+		store := func() {
+			v.SetMapIndex(k, elem)
+		}
+
+		r.iterateOnNestedFields(elem, getElemInfo(elem, info, key), visited, func(f fieldInfo) {
+			if prev := f.store; prev != nil {
+				f.store = func() {
+					prev()
+					store()
+				}
+			} else {
+				f.store = store
+			}
+
+			handle(f)
+		})
+
+		store()
+	}
+}
+
+// getElemKeys returns the indices of elements in a slice or the keys of elements in a map.
+// Keys already in a map are kept as they are and matched case-insensitively, while new keys are in lower case.
+func (r *reader) getElemKeys(v reflect.Value, info fieldInfo) []string {
+	set := map[string]bool{}
+
+	// Keys already in the map by their lower-case forms
+	existing := map[string]string{}
+	if v.Kind() == reflect.Map {
+		for _, k := range v.MapKeys() {
+			existing[strings.ToLower(k.String())] = k.String()
+			set[k.String()] = true
+		}
+	}
+
+	add := func(key string) {
+		if key == "" {
+			return
+		}
+
+		if v.Kind() == reflect.Slice {
+			if i, err := strconv.Atoi(key); err != nil || i < 0 || i >= maxElems || strconv.Itoa(i) != key {
+				return
+			}
+		}
+
+		if orig, ok := existing[strings.ToLower(key)]; ok {
+			set[orig] = true
+		} else {
+			set[strings.ToLower(key)] = true
+		}
+	}
+
+	// Existing elements
+	if v.Kind() == reflect.Slice {
+		for i := 0; i < v.Len(); i++ {
+			add(strconv.Itoa(i))
+		}
+	}
+
+	// Names are only matched if they end with the name of a field of the element (i.e. not SERVERS_COUNT for a sibling field)
+	tElem, _ := getStructElem(v.Type())
+	suffixes := r.getElemSuffixes(tElem, info)
+
+	// Environment variables
+	prefixes := map[string][]string{}
+	if !r.skipEnv && info.envName != skip {
+		prefixes[info.envName+"_"] = suffixes.env
+	}
+	if !r.skipFileEnv && info.fileEnvName != skip {
+		prefixes[info.fileEnvName+"_"] = append(prefixes[info.fileEnvName+"_"], suffixes.fileEnv...)
+	}
+
+	for _, name := range r.envNames() {
+		for prefix, sfx := range prefixes {
+			if rest, ok := strings.CutPrefix(name, prefix); ok {
+				add(matchElemKey(rest, "_", sfx))
+			}
+		}
+	}
+
+	// Command-line flags
+	if !r.skipFlag && info.flagName != skip {
+		for _, name := range r.flagNames() {
+			if rest, ok := strings.CutPrefix(name, info.flagName+"."); ok {
+				add(matchElemKey(rest, ".", suffixes.flag))
+			}
+		}
+	}
+
+	// Configuration documents
+	if len(info.docKeys) > 0 && info.docKeys[0] != skip {
+		for _, doc := range []*document{r.profileDoc, r.doc} {
+			if doc != nil {
+				for _, key := range doc.keys(info.docKeys) {
+					add(key)
+				}
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+
+	if v.Kind() == reflect.Slice {
+		sort.Slice(keys, func(i, j int) bool {
+			a, _ := strconv.Atoi(keys[i])
+			b, _ := strconv.Atoi(keys[j])
+			return a < b
+		})
+	} else {
+		sort.Strings(keys)
+	}
+
+	return keys
+}
+
+// elemSuffixes are the names of the fields of an element for every source relative to the element.
+type elemSuffixes struct {
+	flag, env, fileEnv []string
+}
+
+// getElemSuffixes returns the names of the fields of an element relative to the element (i.e. host, HOST, and HOST_FILE for Upstreams[0].Host).
+// Slices and maps of structs nested in the element are included by their names followed by a separator (i.e. BACKENDS_),
+// since their own elements can have any key.
+func (r *reader) getElemSuffixes(tElem reflect.Type, info fieldInfo) elemSuffixes {
+	var suffixes elemSuffixes
+
+	elem := reflect.New(tElem).Elem()
+	elemInfo := getElemInfo(elem, info, "0")
+
+	add := func(f fieldInfo, container bool) {
+		flagSep, envSep := "", ""
+		if container {
+			flagSep, envSep = ".", "_"
+		}
+
+		if s, ok := strings.CutPrefix(f.flagName, elemInfo.flagName+"."); ok {
+			suffixes.flag = append(suffixes.flag, s+flagSep)
+		}
+		if s, ok := strings.CutPrefix(f.envName, elemInfo.envName+"_"); ok {
+			suffixes.env = append(suffixes.env, s+envSep)
+		}
+		if s, ok := strings.CutPrefix(f.fileEnvName, elemInfo.fileEnvName+"_"); ok {
+			suffixes.fileEnv = append(suffixes.fileEnv, s+envSep)
+		}
+	}
+
+	var walk func(vStruct reflect.Value, parent fieldInfo, visited []reflect.Type)
+	walk = func(vStruct reflect.Value, parent fieldInfo, visited []reflect.Type) {
+		for i := 0; i < vStruct.NumField(); i++ {
+			v := vStruct.Field(i)
+			f := vStruct.Type().Field(i)
+
+			if !v.CanSet() {
+				continue
+			}
+
+			if isTypeSupported(v.Type()) {
+				add(r.getFieldInfo(v, f, parent, false), false)
+				continue
+			}
+
+			if _, ok := getStructElem(v.Type()); ok {
+				add(r.getFieldInfo(v, f, parent, true), true)
+				continue
+			}
+
+			tStruct := v.Type()
+			if tStruct.Kind() == reflect.Ptr {
+				tStruct = tStruct.Elem()
+			}
+
+			if tStruct.Kind() != reflect.Struct || slices.Contains(visited, tStruct) {
+				continue
+			}
+
+			nested := parent
+			if !f.Anonymous || f.Tag.Get(tagFlag) != "" || f.Tag.Get(tagEnv) != "" || f.Tag.Get(tagFileEnv) != "" {
+				nested = r.getFieldInfo(v, f, parent, true)
+			}
+
+			walk(reflect.New(tStruct).Elem(), nested, append(visited, tStruct))
+		}
+	}
+
+	walk(elem, elemInfo, []reflect.Type{tElem})
+
+	return suffixes
+}
+
+// matchElemKey returns the key of an element from the rest of a name after the prefix of a slice or a map (i.e. US_EAST for US_EAST_HOST).
+// The rest should end with the name of a field of the element or continue with the name of a nested slice or map.
+// Keys can contain the separator, and if more than one field matches, the shortest key is returned.
+func matchElemKey(rest, sep string, suffixes []string) string {
+	var key string
+
+	for _, suffix := range suffixes {
+		var k string
+		if strings.HasSuffix(suffix, sep) {
+			if i := strings.Index(rest, sep+suffix); i > 0 {
+				k = rest[:i]
+			}
+		} else {
+			k, _ = strings.CutSuffix(rest, sep+suffix)
+			if k == rest {
+				k = ""
+			}
+		}
+
+		if k != "" && (key == "" || len(k) < len(key)) {
+			key = k
+		}
+	}
+
+	return key
+}
+
+// envNames returns the names of all environment variables including the variables read from .env files.
+// If a profile is set, the names of variables prefixed with the profile are included without the prefix too.
+func (r *reader) envNames() []string {
+	names := []string{}
+
+	add := func(name string) {
+		names = append(names, name)
+		if r.profile != "" {
+			if base, ok := strings.CutPrefix(name, strings.ToUpper(r.profile)+"_"); ok {
+				names = append(names, base)
+			}
+		}
+	}
+
+	for _, kv := range os.Environ() {
+		name, _, _ := strings.Cut(kv, "=")
+		add(name)
+	}
+
+	for name := range r.envVars {
+		add(name)
+	}
+
+	return names
+}
+
+// flagNames returns the names of all flags passed on the command line.
+// Arguments not starting with a letter after dashes (i.e. -5 in -offset -5) are values rather than flags (see getFlagValue).
+func (r *reader) flagNames() []string {
+	args := os.Args[1:]
+	if r.flagSet != nil {
+		args = r.flagArgs
+	}
+
+	names := []string{}
+	for _, arg := range args {
+		if arg == "--" {
+			break
+		}
+
+		if !flagArgRegex.MatchString(arg) {
+			continue
+		}
+
+		name, _, _ := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		names = append(names, name)
+	}
+
+	// Flags already set on a parsed flag set
+	if r.flagSet != nil && r.flagSet.Parsed() {
+		r.flagSet.Visit(func(f *flag.Flag) {
+			if !slices.Contains(names, f.Name) {
+				names = append(names, f.Name)
+			}
+		})
+	}
+
+	return names
+}
+
+// cloneElems returns a copy of a slice of structs or a map of strings to structs in which all elements are copied too.
+func cloneElems(v reflect.Value) reflect.Value {
+	clone := func(elem reflect.Value) reflect.Value {
+		if elem.Kind() == reflect.Ptr {
+			if elem.IsNil() {
+				return elem
+			}
+			return cloneStruct(elem.Elem()).Addr()
+		}
+		return cloneStruct(elem)
+	}
+
+	if v.Kind() == reflect.Slice {
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(clone(v.Index(i)))
+		}
+		return c
+	}
+
+	c := reflect.MakeMapWithSize(v.Type(), v.Len())
+	for iter := v.MapRange(); iter.Next(); {
+		c.SetMapIndex(iter.Key(), clone(iter.Value()))
+	}
+
+	return c
+}
@@ -0,0 +1,415 @@
+package config
+
+import (
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetStructElem(t *testing.T) {
+	type upstream struct {
+		Host string
+	}
+
+	tests := []struct {
+		name         string
+		t            reflect.Type
+		expectedType reflect.Type
+		expectedOK   bool
+	}{
+		{"Slice", reflect.TypeOf([]upstream{}), reflect.TypeOf(upstream{}), true},
+		{"SliceOfPointers", reflect.TypeOf([]*upstream{}), reflect.TypeOf(upstream{}), true},
+		{"Map", reflect.TypeOf(map[string]upstream{}), reflect.TypeOf(upstream{}), true},
+		{"MapOfPointers", reflect.TypeOf(map[string]*upstream{}), reflect.TypeOf(upstream{}), true},
+		{"MapWithIntKeys", reflect.TypeOf(map[int]upstream{}), nil, false},
+		{"SliceOfStrings", reflect.TypeOf([]string{}), nil, false},
+		{"SliceOfTimes", reflect.TypeOf([]time.Time{}), nil, false},
+		{"Struct", reflect.TypeOf(upstream{}), nil, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			typ, ok := getStructElem(tc.t)
+
+			assert.Equal(t, tc.expectedOK, ok)
+			assert.Equal(t, tc.expectedType, typ)
+		})
+	}
+}
+
+func TestGetElemInfo(t *testing.T) {
+	tests := []struct {
+		name         string
+		parent       fieldInfo
+		key          string
+		expectedInfo fieldInfo
+	}{
+		{
+			name: "Index",
+			parent: fieldInfo{
+				name:        "Upstreams",
+				flagName:    "upstreams",
+				envName:     "UPSTREAMS",
+				fileEnvName: "UPSTREAMS",
+				docKeys:     []string{"Upstreams"},
+			},
+			key: "0",
+			expectedInfo: fieldInfo{
+				name:        "Upstreams[0]",
+				flagName:    "upstreams.0",
+				envName:     "UPSTREAMS_0",
+				fileEnvName: "UPSTREAMS_0",
+				docKeys:     []string{"Upstreams", "0"},
+			},
+		},
+		{
+			name: "Key",
+			parent: fieldInfo{
+				name:        "Backends",
+				flagName:    "backends",
+				envName:     "APP_BACKENDS",
+				fileEnvName: "BACKENDS",
+				docKeys:     []string{"backends"},
+			},
+			key: "primary",
+			expectedInfo: fieldInfo{
+				name:        "Backends[primary]",
+				flagName:    "backends.primary",
+				envName:     "APP_BACKENDS_PRIMARY",
+				fileEnvName: "BACKENDS_PRIMARY",
+				docKeys:     []string{"backends", "primary"},
+			},
+		},
+		{
+			name: "Skipped",
+			parent: fieldInfo{
+				name:        "Upstreams",
+				flagName:    skip,
+				envName:     skip,
+				fileEnvName: skip,
+				docKeys:     []string{skip},
+			},
+			key: "0",
+			expectedInfo: fieldInfo{
+				name:        "Upstreams[0]",
+				flagName:    skip,
+				envName:     skip,
+				fileEnvName: skip,
+				docKeys:     []string{skip},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedInfo, getElemInfo(reflect.Value{}, tc.parent, tc.key))
+		})
+	}
+}
+
+func TestPickWithElems(t *testing.T) {
+	type upstream struct {
+		Host    string
+		Port    int `default:"80"`
+		Timeout time.Duration
+	}
+
+	type elemsConfig struct {
+		ElemUpstreams []upstream
+		ElemReplicas  []*upstream
+		ElemBackends  map[string]upstream
+		ElemMirrors   map[string]*upstream
+	}
+
+	setenv := func(t *testing.T, env map[string]string) {
+		for name, val := range env {
+			assert.NoError(t, os.Setenv(name, val))
+		}
+
+		t.Cleanup(func() {
+			for name := range env {
+				assert.NoError(t, os.Unsetenv(name))
+			}
+		})
+	}
+
+	t.Run("IndexedEnv", func(t *testing.T) {
+		setenv(t, map[string]string{
+			"ELEM_UPSTREAMS_0_HOST":    "a.local",
+			"ELEM_UPSTREAMS_1_HOST":    "b.local",
+			"ELEM_UPSTREAMS_1_PORT":    "8080",
+			"ELEM_UPSTREAMS_1_TIMEOUT": "5s",
+			"ELEM_REPLICAS_0_HOST":     "r.local",
+		})
+
+		c := new(elemsConfig)
+		err := Pick(c, SkipFlag())
+		assert.NoError(t, err)
+
+		assert.Equal(t, []upstream{
+			{Host: "a.local", Port: 80},
+			{Host: "b.local", Port: 8080, Timeout: 5 * time.Second},
+		}, c.ElemUpstreams)
+		assert.Equal(t, []*upstream{
+			{Host: "r.local", Port: 80},
+		}, c.ElemReplicas)
+	})
+
+	t.Run("KeyedEnv", func(t *testing.T) {
+		dir := t.TempDir()
+		hostPath := filepath.Join(dir, "host")
+		assert.NoError(t, os.WriteFile(hostPath, []byte("s.local"), 0644))
+
+		setenv(t, map[string]string{
+			"ELEM_BACKENDS_PRIMARY_HOST":        "p.local",
+			"ELEM_BACKENDS_SECONDARY_HOST_FILE": hostPath,
+			"ELEM_MIRRORS_EU_PORT":              "9090",
+		})
+
+		c := new(elemsConfig)
+		err := Pick(c, SkipFlag())
+		assert.NoError(t, err)
+
+		assert.Equal(t, map[string]upstream{
+			"primary":   {Host: "p.local", Port: 80},
+			"secondary": {Host: "s.local", Port: 80},
+		}, c.ElemBackends)
+		assert.Equal(t, map[string]*upstream{
+			"eu": {Port: 9090},
+		}, c.ElemMirrors)
+	})
+
+	t.Run("Flags", func(t *testing.T) {
+		fs := flag.NewFlagSet("app", flag.ContinueOnError)
+		args := []string{
+			"-elem.upstreams.0.host", "a.local",
+			"-elem.upstreams.2.host=c.local",
+			"--elem.backends.primary.port", "8080",
+		}
+
+		c := new(elemsConfig)
+		err := Pick(c, WithFlagSet(fs, args), SkipEnv(), SkipFileEnv())
+		assert.NoError(t, err)
+
+		assert.Equal(t, []upstream{
+			{Host: "a.local", Port: 80},
+			{Port: 80},
+			{Host: "c.local", Port: 80},
+		}, c.ElemUpstreams)
+		assert.Equal(t, map[string]upstream{
+			"primary": {Port: 8080},
+		}, c.ElemBackends)
+	})
+
+	t.Run("Document", func(t *testing.T) {
+		docPath := filepath.Join(t.TempDir(), "config.yaml")
+		doc := "elem_upstreams:\n  - host: a.local\n  - host: b.local\n    port: 8080\nelem_backends:\n  Primary:\n    host: p.local\n"
+		assert.NoError(t, os.WriteFile(docPath, []byte(doc), 0644))
+
+		c := new(elemsConfig)
+		err := Pick(c, SkipFlag(), SkipEnv(), SkipFileEnv(), FromFile(docPath))
+		assert.NoError(t, err)
+
+		assert.Equal(t, []upstream{
+			{Host: "a.local", Port: 80},
+			{Host: "b.local", Port: 8080},
+		}, c.ElemUpstreams)
+		assert.Equal(t, map[string]upstream{
+			"primary": {Host: "p.local", Port: 80},
+		}, c.ElemBackends)
+	})
+
+	t.Run("ExistingElems", func(t *testing.T) {
+		setenv(t, map[string]string{
+			"ELEM_UPSTREAMS_1_HOST": "b.local",
+		})
+
+		c := &elemsConfig{
+			ElemUpstreams: []upstream{{Host: "a.local"}},
+			ElemBackends:  map[string]upstream{"primary": {Host: "p.local"}},
+		}
+
+		err := Pick(c, SkipFlag())
+		assert.NoError(t, err)
+
+		assert.Equal(t, []upstream{
+			{Host: "a.local", Port: 80},
+			{Host: "b.local", Port: 80},
+		}, c.ElemUpstreams)
+		assert.Equal(t, map[string]upstream{
+			"primary": {Host: "p.local", Port: 80},
+		}, c.ElemBackends)
+	})
+
+	t.Run("ExistingMapKeys", func(t *testing.T) {
+		setenv(t, map[string]string{
+			"ELEM_BACKENDS_PRIMARY_PORT": "99",
+			"ELEM_MIRRORS_EU_PORT":       "9090",
+		})
+
+		c := &elemsConfig{
+			ElemBackends: map[string]upstream{"Primary": {Host: "p.local"}},
+			ElemMirrors:  map[string]*upstream{"EU": {Host: "eu.local"}},
+		}
+
+		err := Pick(c, SkipFlag())
+		assert.NoError(t, err)
+
+		// Keys already in the map should be kept as they are
+		assert.Equal(t, map[string]upstream{
+			"Primary": {Host: "p.local", Port: 99},
+		}, c.ElemBackends)
+		assert.Equal(t, map[string]*upstream{
+			"EU": {Host: "eu.local", Port: 9090},
+		}, c.ElemMirrors)
+	})
+
+	t.Run("SiblingFields", func(t *testing.T) {
+		setenv(t, map[string]string{
+			"ELEM_SERVERS_COUNT": "3",
+		})
+
+		type siblingsConfig struct {
+			ElemServers      map[string]upstream
+			ElemServersCount int
+		}
+
+		fs := flag.NewFlagSet("app", flag.ContinueOnError)
+		args := []string{"-elem.servers.count=3", "-elem.servers.primary.host=p.local"}
+
+		c := new(siblingsConfig)
+		err := Pick(c, WithFlagSet(fs, args))
+		assert.NoError(t, err)
+
+		// Names of sibling fields sharing the prefix should not be taken as keys
+		assert.Equal(t, map[string]upstream{
+			"primary": {Host: "p.local", Port: 80},
+		}, c.ElemServers)
+		assert.Equal(t, 3, c.ElemServersCount)
+	})
+
+	t.Run("KeysWithSeparator", func(t *testing.T) {
+		setenv(t, map[string]string{
+			"ELEM_BACKENDS_US_EAST_HOST": "a.local",
+			"ELEM_BACKENDS_US_EAST_PORT": "8080",
+		})
+
+		c := new(elemsConfig)
+		err := Pick(c, SkipFlag())
+		assert.NoError(t, err)
+
+		assert.Equal(t, map[string]upstream{
+			"us_east": {Host: "a.local", Port: 8080},
+		}, c.ElemBackends)
+	})
+
+	t.Run("NestedElems", func(t *testing.T) {
+		setenv(t, map[string]string{
+			"ELEM_REGIONS_EU_ZONES_0_HOST": "z.local",
+		})
+
+		type region struct {
+			Zones []upstream
+		}
+
+		type nestedConfig struct {
+			ElemRegions map[string]region
+		}
+
+		c := new(nestedConfig)
+		err := Pick(c, SkipFlag())
+		assert.NoError(t, err)
+
+		assert.Equal(t, map[string]region{
+			"eu": {Zones: []upstream{{Host: "z.local", Port: 80}}},
+		}, c.ElemRegions)
+	})
+
+	t.Run("InvalidValue", func(t *testing.T) {
+		setenv(t, map[string]string{
+			"ELEM_UPSTREAMS_0_PORT": "invalid",
+		})
+
+		c := new(elemsConfig)
+		err := Pick(c, SkipFlag())
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "ElemUpstreams[0].Port")
+	})
+}
+
+func TestWatchWithElems(t *testing.T) {
+	type backend struct {
+		Host string
+	}
+
+	type elemsConfig struct {
+		sync.Mutex
+		WatchBackends map[string]backend
+	}
+
+	origArgs := os.Args
+	defer func() {
+		os.Args = origArgs
+	}()
+
+	os.Args = []string{"app"}
+
+	hostPath := filepath.Join(t.TempDir(), "host")
+	assert.NoError(t, os.WriteFile(hostPath, []byte("p.local"), 0644))
+
+	assert.NoError(t, os.Setenv("WATCH_BACKENDS_PRIMARY_HOST_FILE", hostPath))
+	defer func() {
+		assert.NoError(t, os.Unsetenv("WATCH_BACKENDS_PRIMARY_HOST_FILE"))
+	}()
+
+	ch := make(chan Update, 10)
+	c := new(elemsConfig)
+	w, err := WatchContext(context.Background(), c, []chan Update{ch}, PollInterval(time.Hour))
+	assert.NoError(t, err)
+	defer w.Close()
+
+	assert.Equal(t, Update{"WatchBackends[primary].Host", "p.local"}, <-ch)
+
+	// New values should be stored in the map
+	assert.NoError(t, os.WriteFile(hostPath, []byte("q.local"), 0644))
+	w.Reload()
+
+	assert.Equal(t, Update{"WatchBackends[primary].Host", "q.local"}, <-ch)
+
+	c.Lock()
+	assert.Equal(t, map[string]backend{"primary": {Host: "q.local"}}, c.WatchBackends)
+	c.Unlock()
+
+	// New elements should be discovered and elements no longer in any source should be removed
+	assert.NoError(t, os.Setenv("WATCH_BACKENDS_SECONDARY_HOST", "s.local"))
+	defer func() {
+		assert.NoError(t, os.Unsetenv("WATCH_BACKENDS_SECONDARY_HOST"))
+	}()
+	assert.NoError(t, os.Unsetenv("WATCH_BACKENDS_PRIMARY_HOST_FILE"))
+	w.Reload()
+
+	assert.Equal(t, Update{"WatchBackends[secondary].Host", "s.local"}, <-ch)
+
+	c.Lock()
+	assert.Equal(t, map[string]backend{"secondary": {Host: "s.local"}}, c.WatchBackends)
+	c.Unlock()
+
+	assert.NotContains(t, w.r.filesToFields, hostPath)
+
+	// New values should be set on the new elements
+	assert.NoError(t, os.Setenv("WATCH_BACKENDS_SECONDARY_HOST", "t.local"))
+	w.Reload()
+
+	assert.Equal(t, Update{"WatchBackends[secondary].Host", "t.local"}, <-ch)
+
+	c.Lock()
+	assert.Equal(t, map[string]backend{"secondary": {Host: "t.local"}}, c.WatchBackends)
+	c.Unlock()
+}
@@ -0,0 +1,194 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// readEnvFile parses a .env file and adds its variables to the given map.
+// Each line is in the form of NAME=value and may start with export.
+// Blank lines and lines starting with # are ignored.
+// Values can be single-quoted (literal), double-quoted (escape sequences), or unquoted (trailing comments are removed).
+// References to other variables in double-quoted and unquoted values are expanded (see expandEnv).
+func readEnvFile(path string, vars map[string]string, lookup func(string) (string, bool)) error {
+	b, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return err
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		line = strings.TrimSpace(strings.TrimPrefix(line, "export "))
+
+		name, val, ok := strings.Cut(line, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" || strings.ContainsAny(name, " \t") {
+			return fmt.Errorf("invalid line %d in %s", n, path)
+		}
+
+		val = strings.TrimSpace(val)
+
+		// A quoted value can be followed by a comment (i.e. "bar" # comment)
+		if quoted, rest, ok := cutQuoted(val); ok && (rest == "" || strings.HasPrefix(rest, "#")) {
+			val = quoted
+		}
+
+		switch {
+		case len(val) >= 2 && val[0] == '\'' && val[len(val)-1] == '\'':
+			val = val[1 : len(val)-1]
+
+		case len(val) >= 2 && val[0] == '"' && val[len(val)-1] == '"':
+			if val, err = strconv.Unquote(val); err != nil {
+				return fmt.Errorf("invalid value at line %d in %s: %s", n, path, err)
+			}
+			val = expandEnv(val, lookup)
+
+		default:
+			if i := strings.Index(val, " #"); i >= 0 {
+				val = strings.TrimSpace(val[:i])
+			}
+			val = expandEnv(val, lookup)
+		}
+
+		vars[name] = val
+	}
+
+	return scanner.Err()
+}
+
+// cutQuoted returns a single-quoted or double-quoted value at the start of a string and the rest of the string after the closing quote.
+// Quotes escaped by backslashes in double-quoted values do not close the value.
+func cutQuoted(s string) (string, string, bool) {
+	if len(s) < 2 || (s[0] != '\'' && s[0] != '"') {
+		return "", "", false
+	}
+
+	for i := 1; i < len(s); i++ {
+		switch {
+		case s[0] == '"' && s[i] == '\\':
+			i++
+		case s[i] == s[0]:
+			return s[:i+1], strings.TrimSpace(s[i+1:]), true
+		}
+	}
+
+	return "", "", false
+}
+
+// expandEnv replaces references to environment variables in a string.
+//
+//	${VAR}           -->  the value of VAR or an empty string if VAR is not set
+//	${VAR:-default}  -->  the value of VAR or default if VAR is not set or empty
+//	$$               -->  $
+//
+// Any other use of $ is kept as it is.
+func expandEnv(s string, lookup func(string) (string, bool)) string {
+	if !strings.Contains(s, "$") {
+		return s
+	}
+
+	var b strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		if s[i] != '$' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+
+		if s[i+1] == '$' {
+			b.WriteByte('$')
+			i++
+			continue
+		}
+
+		end := strings.IndexByte(s[i:], '}')
+		if s[i+1] != '{' || end < 0 {
+			b.WriteByte(s[i])
+			continue
+		}
+
+		expr := s[i+2 : i+end]
+		name, def, hasDef := strings.Cut(expr, ":-")
+
+		if val, ok := lookup(name); ok && (val != "" || !hasDef) {
+			b.WriteString(val)
+		} else {
+			b.WriteString(def)
+		}
+
+		i += end
+	}
+
+	return b.String()
+}
+
+// loadEnvFiles reads the .env files if any is specified.
+// A variable in a file overrides the same variable in the files before it.
+// If a profile is set, the profile-specific version of each file (i.e. .env.staging) is read after it if exists.
+func (r *reader) loadEnvFiles() error {
+	if len(r.envFiles) == 0 {
+		return nil
+	}
+
+	vars := map[string]string{}
+	lookup := func(name string) (string, bool) {
+		if val, ok := os.LookupEnv(name); ok {
+			return val, true
+		}
+		val, ok := vars[name]
+		return val, ok
+	}
+
+	for _, path := range r.withProfilePaths(r.envFiles) {
+		r.log(2, "Reading environment file %s ...", path)
+
+		if err := readEnvFile(path, vars, lookup); err != nil {
+			return err
+		}
+	}
+
+	r.envVars = vars
+
+	return nil
+}
+
+// lookupEnv returns the value of an environment variable.
+// Variables set in the process environment take precedence over the variables read from .env files.
+func (r *reader) lookupEnv(name string) (string, bool) {
+	if val, ok := os.LookupEnv(name); ok {
+		return val, true
+	}
+
+	val, ok := r.envVars[name]
+	return val, ok
+}
+
+// getEnv returns the value of an environment variable or an empty string if it is not set.
+func (r *reader) getEnv(name string) string {
+	val, _ := r.lookupEnv(name)
+	return val
+}
+
+// expandValue replaces references to environment variables in a value read for a field if the ExpandEnv option is set.
+func (r *reader) expandValue(f fieldInfo, val string) string {
+	if !r.expandEnv {
+		return val
+	}
+
+	expanded := expandEnv(val, r.lookupEnv)
+	if expanded != val {
+		r.log(5, "[%s] value expanded: %s", f.name, maskValue(f.secret, expanded))
+	}
+
+	return expanded
+}
@@ -0,0 +1,180 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpandEnvReferences(t *testing.T) {
+	vars := map[string]string{
+		"HOST":  "localhost",
+		"PORT":  "8080",
+		"EMPTY": "",
+	}
+
+	lookup := func(name string) (string, bool) {
+		val, ok := vars[name]
+		return val, ok
+	}
+
+	tests := []struct {
+		name          string
+		s             string
+		expectedValue string
+	}{
+		{"NoReference", "localhost:8080", "localhost:8080"},
+		{"Reference", "${HOST}:${PORT}", "localhost:8080"},
+		{"Unset", "${UNSET}", ""},
+		{"Empty", "${EMPTY}", ""},
+		{"DefaultUnset", "${UNSET:-127.0.0.1}", "127.0.0.1"},
+		{"DefaultEmpty", "${EMPTY:-127.0.0.1}", "127.0.0.1"},
+		{"DefaultSet", "${HOST:-127.0.0.1}", "localhost"},
+		{"Escaped", "$${HOST}", "${HOST}"},
+		{"NoBraces", "$HOST", "$HOST"},
+		{"Unterminated", "${HOST", "${HOST"},
+		{"Trailing", "cost$", "cost$"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedValue, expandEnv(tc.s, lookup))
+		})
+	}
+}
+
+func TestReadEnvFile(t *testing.T) {
+	assert.NoError(t, os.Setenv("ENV_FILE_HOME", "/home/app"))
+	defer func() {
+		assert.NoError(t, os.Unsetenv("ENV_FILE_HOME"))
+	}()
+
+	tests := []struct {
+		name          string
+		content       string
+		expectedVars  map[string]string
+		expectedError string
+	}{
+		{
+			name:          "InvalidLine",
+			content:       "LEVEL\n",
+			expectedError: "invalid line 1 in %s",
+		},
+		{
+			name:          "InvalidQuotes",
+			content:       "LEVEL=\"debug\\\"\n",
+			expectedError: "invalid value at line 1 in %s: invalid syntax",
+		},
+		{
+			name: "Success",
+			content: `# comment
+
+export LEVEL=debug
+NAME = app # comment
+SINGLE='${ENV_FILE_HOME}'
+DOUBLE="line\none"
+DATA=${ENV_FILE_HOME}/data
+CACHE=${DATA}/cache
+EMPTY=
+HASH="bar # baz" # comment
+QUOTED='bar' # comment
+ESCAPE="say \"hi\"" # comment
+`,
+			expectedVars: map[string]string{
+				"LEVEL":  "debug",
+				"NAME":   "app",
+				"SINGLE": "${ENV_FILE_HOME}",
+				"DOUBLE": "line\none",
+				"DATA":   "/home/app/data",
+				"CACHE":  "/home/app/data/cache",
+				"EMPTY":  "",
+				"HASH":   "bar # baz",
+				"QUOTED": "bar",
+				"ESCAPE": "say \"hi\"",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), ".env")
+			assert.NoError(t, os.WriteFile(path, []byte(tc.content), 0644))
+
+			vars := map[string]string{}
+			lookup := func(name string) (string, bool) {
+				if val, ok := os.LookupEnv(name); ok {
+					return val, true
+				}
+				val, ok := vars[name]
+				return val, ok
+			}
+
+			err := readEnvFile(path, vars, lookup)
+
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, fmt.Sprintf(tc.expectedError, path))
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedVars, vars)
+			}
+		})
+	}
+}
+
+func TestPickWithEnvFile(t *testing.T) {
+	type envConfig struct {
+		EnvFileName  string
+		EnvFileLevel string
+		EnvFileAddr  string `default:"${ENV_FILE_HOST:-localhost}:${ENV_FILE_PORT}"`
+		EnvFileToken string
+	}
+
+	dir := t.TempDir()
+	envPath := filepath.Join(dir, ".env")
+	localPath := filepath.Join(dir, ".env.local")
+
+	assert.NoError(t, os.WriteFile(envPath, []byte("ENV_FILE_NAME=app\nENV_FILE_LEVEL=info\nENV_FILE_PORT=8080\n"), 0644))
+	assert.NoError(t, os.WriteFile(localPath, []byte("ENV_FILE_LEVEL=debug\n"), 0644))
+
+	assert.NoError(t, os.Setenv("ENV_FILE_NAME", "service"))
+	assert.NoError(t, os.Setenv("ENV_FILE_TOKEN", "$${ENV_FILE_NAME}"))
+	defer func() {
+		assert.NoError(t, os.Unsetenv("ENV_FILE_NAME"))
+		assert.NoError(t, os.Unsetenv("ENV_FILE_TOKEN"))
+	}()
+
+	t.Run("MissingFile", func(t *testing.T) {
+		c := new(envConfig)
+		err := Pick(c, SkipFlag(), WithEnvFile(filepath.Join(dir, "missing")))
+		assert.Error(t, err)
+	})
+
+	t.Run("WithoutExpansion", func(t *testing.T) {
+		c := new(envConfig)
+		err := Pick(c, SkipFlag(), WithEnvFile(envPath, localPath))
+		assert.NoError(t, err)
+
+		assert.Equal(t, &envConfig{
+			EnvFileName:  "service",
+			EnvFileLevel: "debug",
+			EnvFileAddr:  "${ENV_FILE_HOST:-localhost}:${ENV_FILE_PORT}",
+			EnvFileToken: "$${ENV_FILE_NAME}",
+		}, c)
+	})
+
+	t.Run("WithExpansion", func(t *testing.T) {
+		c := new(envConfig)
+		err := Pick(c, SkipFlag(), WithEnvFile(envPath, localPath), ExpandEnv())
+		assert.NoError(t, err)
+
+		assert.Equal(t, &envConfig{
+			EnvFileName:  "service",
+			EnvFileLevel: "debug",
+			EnvFileAddr:  "localhost:8080",
+			EnvFileToken: "${ENV_FILE_NAME}",
+		}, c)
+	})
+}
@@ -1,8 +1,11 @@
 package config_test
 
 import (
+	"context"
 	"fmt"
+	"net"
 	"net/url"
+	"reflect"
 	"sync"
 	"time"
 
@@ -63,3 +66,102 @@ func ExampleWatch() {
 	close, _ := config.Watch(&params, []chan config.Update{ch})
 	defer close()
 }
+
+func ExampleWatchContext() {
+	var params = struct {
+		sync.Mutex
+		LogLevel string
+	}{
+		LogLevel: "info", // default
+	}
+
+	// The watcher stops when the context is cancelled (e.g. when the application receives a termination signal).
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch := make(chan config.Update)
+
+	w, err := config.WatchContext(ctx, &params, []chan config.Update{ch})
+	if err != nil {
+		return
+	}
+
+	// Updates are delivered in order and the channel is closed once the watcher is fully stopped.
+	go func() {
+		for update := range ch {
+			fmt.Printf("%s is updated\n", update.Name)
+		}
+	}()
+
+	cancel()
+	<-w.Done()
+}
+
+func ExampleOnChange() {
+	type Config struct {
+		sync.Mutex
+		LogLevel string
+		Port     uint16
+	}
+
+	params := &Config{
+		LogLevel: "info", // default
+		Port:     8080,   // default
+	}
+
+	w, err := config.WatchContext(context.Background(), params, nil)
+	if err != nil {
+		return
+	}
+	defer w.Close()
+
+	// The function is called with the old and new log levels only when the log level changes.
+	_ = config.OnChange(w, func(c *Config) string { return c.LogLevel }, func(old, new string) {
+		fmt.Printf("log level is changed from %s to %s\n", old, new)
+	})
+
+	// The function is called once with the old and new snapshots of the config for all values updated together.
+	_ = config.OnUpdate(w, func(old, new *Config) {
+		fmt.Printf("config is updated: %s/%d\n", new.LogLevel, new.Port)
+	})
+}
+
+func ExampleNewLive() {
+	type Config struct {
+		LogLevel string
+		Port     uint16
+	}
+
+	live, err := config.NewLive(context.Background(), &Config{
+		LogLevel: "info", // default
+		Port:     8080,   // default
+	})
+	if err != nil {
+		return
+	}
+	defer live.Close()
+
+	// Load returns a consistent snapshot of the config without any locking.
+	cfg := live.Load()
+	fmt.Printf("log level: %s\n", cfg.LogLevel)
+}
+
+func ExampleRegisterDecoder() {
+	// You can register a decoder for any type that is not supported out of the box.
+	// Types implementing the encoding.TextUnmarshaler or flag.Value interfaces do not need a decoder.
+	config.RegisterDecoder(reflect.TypeOf(net.IPNet{}), func(val string) (interface{}, error) {
+		_, ipNet, err := net.ParseCIDR(val)
+		if err != nil {
+			return nil, err
+		}
+		return *ipNet, nil
+	})
+
+	var params = struct {
+		AllowedNetworks []net.IPNet
+	}{}
+
+	_ = config.Pick(&params)
+
+	fmt.Printf("%+v\n", params)
+}
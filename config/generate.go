@@ -0,0 +1,379 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const jsonSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// getFields returns the information for all fields of a struct.
+// The document format determines which struct tags are used for document keys.
+// Elements are not discovered from any source, and a placeholder element stands for all elements of a slice or a map of structs (i.e. Upstreams[*].Host).
+func getFields(config interface{}, format string, opts []Option) ([]fieldInfo, error) {
+	c := readerFromEnv()
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	v, err := validateStruct(config)
+	if err != nil {
+		return nil, err
+	}
+
+	if format != "" {
+		c.doc = &document{format: format}
+	}
+
+	c.placeholders = true
+
+	fields := []fieldInfo{}
+	c.iterateOnCopy(v, func(f fieldInfo) {
+		fields = append(fields, f)
+	})
+
+	return fields, nil
+}
+
+// isPlaceholder determines whether or not a field belongs to a placeholder element (see getFields).
+func isPlaceholder(f fieldInfo) bool {
+	return strings.Contains(f.name, "["+placeholderKey+"]")
+}
+
+// GenerateMarkdown generates a Markdown table documenting all fields of a config struct.
+// The table lists the type, default value, flag name, environment variable names, and description (see the desc tag) of every field.
+// The same options passed to Pick or Watch should be passed to GenerateMarkdown too.
+func GenerateMarkdown(config interface{}, opts ...Option) (string, error) {
+	fields, err := getFields(config, "", opts)
+	if err != nil {
+		return "", err
+	}
+
+	code := func(s string) string {
+		if s == "" || s == skip {
+			return ""
+		}
+		return "`" + strings.ReplaceAll(s, "|", `\|`) + "`"
+	}
+
+	buf := new(bytes.Buffer)
+	fmt.Fprintln(buf, "| Field | Type | Default | Flag | Environment Variable | File Environment Variable | Description |")
+	fmt.Fprintln(buf, "|-------|------|---------|------|----------------------|---------------------------|-------------|")
+
+	for _, f := range fields {
+		flagName := f.flagName
+		if flagName != skip {
+			flagName = "-" + flagName
+		}
+
+		fmt.Fprintf(buf, "| %s | %s | %s | %s | %s | %s | %s |\n",
+			code(f.name),
+			code(f.value.Type().String()),
+			code(maskValue(f.secret, getDefaultValue(f))),
+			code(flagName),
+			code(f.envName),
+			code(f.fileEnvName),
+			strings.ReplaceAll(f.desc, "|", `\|`),
+		)
+	}
+
+	return buf.String(), nil
+}
+
+// GenerateEnvFile generates a sample environment file (i.e. .env.example) for a config struct.
+// Every environment variable is set to the default value of its field, and fields holding secrets are left empty.
+// Fields of elements in slices and maps of structs are left out, since their names depend on the indices or keys of elements.
+// The same options passed to Pick or Watch should be passed to GenerateEnvFile too.
+func GenerateEnvFile(config interface{}, opts ...Option) (string, error) {
+	fields, err := getFields(config, "", opts)
+	if err != nil {
+		return "", err
+	}
+
+	buf := new(bytes.Buffer)
+
+	for _, f := range fields {
+		if f.envName == skip || isPlaceholder(f) {
+			continue
+		}
+
+		if buf.Len() > 0 {
+			fmt.Fprintln(buf)
+		}
+
+		if f.desc != "" {
+			fmt.Fprintf(buf, "# %s\n", f.desc)
+		}
+		fmt.Fprintf(buf, "# Type: %s\n", f.value.Type())
+
+		val := getDefaultValue(f)
+		if f.secret {
+			val = ""
+		}
+
+		fmt.Fprintf(buf, "%s=%s\n", f.envName, quoteEnvValue(val))
+	}
+
+	return buf.String(), nil
+}
+
+// quoteEnvValue quotes a value in an environment file if needed.
+func quoteEnvValue(val string) string {
+	if strings.ContainsAny(val, " \t\n\"'#$\\`") {
+		return strconv.Quote(val)
+	}
+	return val
+}
+
+// GenerateKubernetes generates a skeleton for a Kubernetes ConfigMap and a Kubernetes Secret with the given name for a config struct.
+// The ConfigMap has an entry for every environment variable set to the default value of its field,
+// and the Secret has an empty entry for every environment variable of a field holding a secret.
+// Fields of elements in slices and maps of structs are left out, since their names depend on the indices or keys of elements.
+// The same options passed to Pick or Watch should be passed to GenerateKubernetes too.
+func GenerateKubernetes(config interface{}, name string, opts ...Option) (string, error) {
+	fields, err := getFields(config, "", opts)
+	if err != nil {
+		return "", err
+	}
+
+	configMap, secret := new(bytes.Buffer), new(bytes.Buffer)
+
+	for _, f := range fields {
+		if f.envName == skip || isPlaceholder(f) {
+			continue
+		}
+
+		buf, val := configMap, getDefaultValue(f)
+		if f.secret {
+			buf, val = secret, ""
+		}
+
+		if f.desc != "" {
+			fmt.Fprintf(buf, "  # %s\n", f.desc)
+		}
+
+		// A JSON string is a valid YAML string
+		fmt.Fprintf(buf, "  %s: %s\n", f.envName, strconv.Quote(val))
+	}
+
+	buf := new(bytes.Buffer)
+
+	if configMap.Len() > 0 {
+		fmt.Fprintf(buf, "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: %s\ndata:\n%s", name, configMap)
+	}
+
+	if secret.Len() > 0 {
+		if buf.Len() > 0 {
+			fmt.Fprintln(buf, "---")
+		}
+		fmt.Fprintf(buf, "apiVersion: v1\nkind: Secret\nmetadata:\n  name: %s\ntype: Opaque\nstringData:\n%s", name, secret)
+	}
+
+	return buf.String(), nil
+}
+
+// GenerateJSONSchema generates a JSON Schema for configuration documents (see FromFile option) of a config struct.
+// The schema includes the types, default values, descriptions (see the desc tag), and validation rules (see the validation tags) of all fields.
+// Document keys are determined using json struct tags.
+// Slices and maps of structs are described by array and object schemas with the schema of their elements (see items and additionalProperties).
+func GenerateJSONSchema(config interface{}, opts ...Option) (string, error) {
+	fields, err := getFields(config, formatJSON, opts)
+	if err != nil {
+		return "", err
+	}
+
+	root := newObjectSchema()
+	root["$schema"] = jsonSchemaDraft
+
+	for _, f := range fields {
+		if len(f.docKeys) == 0 || f.docKeys[0] == skip {
+			continue
+		}
+
+		// Find or create the schemas of parent objects
+		parent := root
+		for i, key := range f.docKeys[:len(f.docKeys)-1] {
+			switch key {
+			case placeholderIndex:
+				parent = parent["items"].(map[string]interface{})
+				continue
+			case placeholderKey:
+				parent = parent["additionalProperties"].(map[string]interface{})
+				continue
+			}
+
+			props := parent["properties"].(map[string]interface{})
+			obj, ok := props[key].(map[string]interface{})
+			if !ok {
+				switch f.docKeys[i+1] {
+				case placeholderIndex:
+					obj = map[string]interface{}{"type": "array", "items": newObjectSchema()}
+				case placeholderKey:
+					obj = map[string]interface{}{"type": "object", "additionalProperties": newObjectSchema()}
+				default:
+					obj = newObjectSchema()
+				}
+				props[key] = obj
+			}
+			parent = obj
+		}
+
+		key := f.docKeys[len(f.docKeys)-1]
+		parent["properties"].(map[string]interface{})[key] = getFieldSchema(f)
+
+		if f.validation.required {
+			required, _ := parent["required"].([]string)
+			parent["required"] = append(required, key)
+		}
+	}
+
+	b, err := json.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(b) + "\n", nil
+}
+
+// newObjectSchema returns the JSON Schema for an object without any properties yet.
+func newObjectSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type":       "object",
+		"properties": map[string]interface{}{},
+	}
+}
+
+// getFieldSchema returns the JSON Schema for a field.
+func getFieldSchema(f fieldInfo) map[string]interface{} {
+	t := f.value.Type()
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	schema := getTypeSchema(t, f.layout)
+
+	if f.desc != "" {
+		schema["description"] = f.desc
+	}
+
+	if def := getDefaultValue(f); def != "" && !f.secret {
+		if val, ok := parseSchemaValue(schema, def, f.listSep); ok {
+			schema["default"] = val
+		}
+	}
+
+	if f.validation.oneof != "" {
+		enum := []interface{}{}
+		for _, s := range strings.Split(f.validation.oneof, "|") {
+			if val, ok := parseSchemaValue(itemsSchema(schema), s, f.listSep); ok {
+				enum = append(enum, val)
+			}
+		}
+		itemsSchema(schema)["enum"] = enum
+	}
+
+	limits := map[string][2]string{
+		"integer": {"minimum", "maximum"},
+		"number":  {"minimum", "maximum"},
+		"string":  {"minLength", "maxLength"},
+		"array":   {"minItems", "maxItems"},
+		"object":  {"minProperties", "maxProperties"},
+	}
+
+	// Limits for durations and times are not supported by JSON Schema
+	if names, ok := limits[schema["type"].(string)]; ok && schema["format"] == nil && t != reflect.TypeOf(time.Duration(0)) {
+		for i, limit := range []string{f.validation.min, f.validation.max} {
+			if limit == "" {
+				continue
+			}
+			if val, err := strconv.ParseFloat(limit, 64); err == nil {
+				schema[names[i]] = val
+			}
+		}
+	}
+
+	if f.validation.pattern != "" {
+		itemsSchema(schema)["pattern"] = f.validation.pattern
+	}
+
+	return schema
+}
+
+// getTypeSchema returns the JSON Schema for a type.
+func getTypeSchema(t reflect.Type, layout string) map[string]interface{} {
+	switch {
+	case t == reflect.TypeOf(time.Duration(0)):
+		return map[string]interface{}{"type": "string"}
+	case t == reflect.TypeOf(time.Time{}):
+		if layout == time.RFC3339 {
+			return map[string]interface{}{"type": "string", "format": "date-time"}
+		}
+		return map[string]interface{}{"type": "string"}
+	case t == reflect.TypeOf(url.URL{}):
+		return map[string]interface{}{"type": "string", "format": "uri"}
+	case t == reflect.TypeOf(regexp.Regexp{}):
+		return map[string]interface{}{"type": "string", "format": "regex"}
+	case hasDecoder(t):
+		return map[string]interface{}{"type": "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice:
+		return map[string]interface{}{"type": "array", "items": getTypeSchema(t.Elem(), layout)}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": getTypeSchema(t.Elem(), layout)}
+	default:
+		return map[string]interface{}{"type": "string"}
+	}
+}
+
+// itemsSchema returns the schema of items for an array schema or the schema itself otherwise.
+func itemsSchema(schema map[string]interface{}) map[string]interface{} {
+	if items, ok := schema["items"].(map[string]interface{}); ok {
+		return items
+	}
+	return schema
+}
+
+// parseSchemaValue converts a string value to a JSON value of the given schema type.
+func parseSchemaValue(schema map[string]interface{}, val, listSep string) (interface{}, bool) {
+	switch schema["type"] {
+	case "boolean":
+		b, err := strconv.ParseBool(val)
+		return b, err == nil
+	case "integer":
+		i, err := strconv.ParseInt(val, 10, 64)
+		return i, err == nil
+	case "number":
+		f, err := strconv.ParseFloat(val, 64)
+		return f, err == nil
+	case "array":
+		items := []interface{}{}
+		for _, s := range strings.Split(val, listSep) {
+			item, ok := parseSchemaValue(itemsSchema(schema), s, listSep)
+			if !ok {
+				return nil, false
+			}
+			items = append(items, item)
+		}
+		return items, true
+	case "object":
+		return nil, false
+	default:
+		return val, true
+	}
+}
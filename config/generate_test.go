@@ -0,0 +1,396 @@
+package config
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type generated struct {
+	Name     string        `default:"app" desc:"Name of the service"`
+	Port     int           `default:"8080" min:"1" max:"65535" required:"true"`
+	Timeout  time.Duration `default:"10s"`
+	Tags     []string      `desc:"Tags for the service"`
+	Password string        `desc:"Password for the database"`
+	Internal string        `env:"-" json:"-"`
+	Log      struct {
+		Level string `json:"level" default:"info" oneof:"debug|info|warn|error" desc:"Log level"`
+	}
+}
+
+func TestGenerateMarkdown(t *testing.T) {
+	t.Run("InvalidConfig", func(t *testing.T) {
+		out, err := GenerateMarkdown(new(int))
+		assert.EqualError(t, err, "a non-struct type is passed")
+		assert.Empty(t, out)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		out, err := GenerateMarkdown(&generated{Password: "s3cr3t"}, PrefixEnv("APP_"))
+		assert.NoError(t, err)
+
+		expected := "" +
+			"| Field | Type | Default | Flag | Environment Variable | File Environment Variable | Description |\n" +
+			"|-------|------|---------|------|----------------------|---------------------------|-------------|\n" +
+			"| `Name` | `string` | `app` | `-name` | `APP_NAME` | `NAME_FILE` | Name of the service |\n" +
+			"| `Port` | `int` | `8080` | `-port` | `APP_PORT` | `PORT_FILE` |  |\n" +
+			"| `Timeout` | `time.Duration` | `10s` | `-timeout` | `APP_TIMEOUT` | `TIMEOUT_FILE` |  |\n" +
+			"| `Tags` | `[]string` |  | `-tags` | `APP_TAGS` | `TAGS_FILE` | Tags for the service |\n" +
+			"| `Password` | `string` | `*****` | `-password` | `APP_PASSWORD` | `PASSWORD_FILE` | Password for the database |\n" +
+			"| `Internal` | `string` |  | `-internal` |  | `INTERNAL_FILE` |  |\n" +
+			"| `Log.Level` | `string` | `info` | `-log.level` | `APP_LOG_LEVEL` | `LOG_LEVEL_FILE` | Log level |\n"
+
+		assert.Equal(t, expected, out)
+	})
+}
+
+func TestGenerateEnvFile(t *testing.T) {
+	t.Run("InvalidConfig", func(t *testing.T) {
+		out, err := GenerateEnvFile(new(int))
+		assert.EqualError(t, err, "a non-struct type is passed")
+		assert.Empty(t, out)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		out, err := GenerateEnvFile(&generated{Password: "s3cr3t"})
+		assert.NoError(t, err)
+
+		expected := "" +
+			"# Name of the service\n" +
+			"# Type: string\n" +
+			"NAME=app\n" +
+			"\n" +
+			"# Type: int\n" +
+			"PORT=8080\n" +
+			"\n" +
+			"# Type: time.Duration\n" +
+			"TIMEOUT=10s\n" +
+			"\n" +
+			"# Tags for the service\n" +
+			"# Type: []string\n" +
+			"TAGS=\n" +
+			"\n" +
+			"# Password for the database\n" +
+			"# Type: string\n" +
+			"PASSWORD=\n" +
+			"\n" +
+			"# Log level\n" +
+			"# Type: string\n" +
+			"LOG_LEVEL=info\n"
+
+		assert.Equal(t, expected, out)
+	})
+}
+
+func TestQuoteEnvValue(t *testing.T) {
+	tests := []struct {
+		name          string
+		val           string
+		expectedValue string
+	}{
+		{"Empty", "", ""},
+		{"Plain", "info", "info"},
+		{"Space", "hello world", `"hello world"`},
+		{"Quote", `say "hi"`, `"say \"hi\""`},
+		{"Dollar", "$HOME", `"$HOME"`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedValue, quoteEnvValue(tc.val))
+		})
+	}
+}
+
+func TestGenerateKubernetes(t *testing.T) {
+	t.Run("InvalidConfig", func(t *testing.T) {
+		out, err := GenerateKubernetes(new(int), "app")
+		assert.EqualError(t, err, "a non-struct type is passed")
+		assert.Empty(t, out)
+	})
+
+	t.Run("NoSecret", func(t *testing.T) {
+		c := &struct {
+			Level string `default:"info"`
+		}{}
+
+		out, err := GenerateKubernetes(c, "app")
+		assert.NoError(t, err)
+
+		expected := "" +
+			"apiVersion: v1\n" +
+			"kind: ConfigMap\n" +
+			"metadata:\n" +
+			"  name: app\n" +
+			"data:\n" +
+			"  LEVEL: \"info\"\n"
+
+		assert.Equal(t, expected, out)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		out, err := GenerateKubernetes(&generated{}, "app")
+		assert.NoError(t, err)
+
+		expected := "" +
+			"apiVersion: v1\n" +
+			"kind: ConfigMap\n" +
+			"metadata:\n" +
+			"  name: app\n" +
+			"data:\n" +
+			"  # Name of the service\n" +
+			"  NAME: \"app\"\n" +
+			"  PORT: \"8080\"\n" +
+			"  TIMEOUT: \"10s\"\n" +
+			"  # Tags for the service\n" +
+			"  TAGS: \"\"\n" +
+			"  # Log level\n" +
+			"  LOG_LEVEL: \"info\"\n" +
+			"---\n" +
+			"apiVersion: v1\n" +
+			"kind: Secret\n" +
+			"metadata:\n" +
+			"  name: app\n" +
+			"type: Opaque\n" +
+			"stringData:\n" +
+			"  # Password for the database\n" +
+			"  PASSWORD: \"\"\n"
+
+		assert.Equal(t, expected, out)
+	})
+}
+
+func TestGenerateJSONSchema(t *testing.T) {
+	t.Run("InvalidConfig", func(t *testing.T) {
+		out, err := GenerateJSONSchema(new(int))
+		assert.EqualError(t, err, "a non-struct type is passed")
+		assert.Empty(t, out)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		out, err := GenerateJSONSchema(&generated{Password: "s3cr3t"})
+		assert.NoError(t, err)
+
+		expected := `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "properties": {
+    "Log": {
+      "properties": {
+        "level": {
+          "default": "info",
+          "description": "Log level",
+          "enum": [
+            "debug",
+            "info",
+            "warn",
+            "error"
+          ],
+          "type": "string"
+        }
+      },
+      "type": "object"
+    },
+    "Name": {
+      "default": "app",
+      "description": "Name of the service",
+      "type": "string"
+    },
+    "Password": {
+      "description": "Password for the database",
+      "type": "string"
+    },
+    "Port": {
+      "default": 8080,
+      "maximum": 65535,
+      "minimum": 1,
+      "type": "integer"
+    },
+    "Tags": {
+      "description": "Tags for the service",
+      "items": {
+        "type": "string"
+      },
+      "type": "array"
+    },
+    "Timeout": {
+      "default": "10s",
+      "type": "string"
+    }
+  },
+  "required": [
+    "Port"
+  ],
+  "type": "object"
+}
+`
+
+		assert.Equal(t, expected, out)
+	})
+}
+
+func TestGetFieldSchema(t *testing.T) {
+	type schemaStruct struct {
+		Ports   []int             `default:"80,443" min:"1" max:"3"`
+		Levels  []string          `oneof:"debug|info"`
+		Labels  map[string]string `max:"5"`
+		Ratio   *float64          `default:"0.5" max:"1"`
+		Enabled bool              `default:"true"`
+		Since   time.Time
+		Code    string `pattern:"^[a-z]+$" min:"2"`
+	}
+
+	v, _ := validateStruct(&schemaStruct{})
+	fields := []fieldInfo{}
+	readerFromEnv().iterateOnFields(v, func(f fieldInfo) {
+		fields = append(fields, f)
+	})
+
+	expected := []map[string]interface{}{
+		{"type": "array", "items": map[string]interface{}{"type": "integer"}, "default": []interface{}{int64(80), int64(443)}, "minItems": float64(1), "maxItems": float64(3)},
+		{"type": "array", "items": map[string]interface{}{"type": "string", "enum": []interface{}{"debug", "info"}}},
+		{"type": "object", "additionalProperties": map[string]interface{}{"type": "string"}, "maxProperties": float64(5)},
+		{"type": "number", "default": 0.5, "maximum": float64(1)},
+		{"type": "boolean", "default": true},
+		{"type": "string", "format": "date-time"},
+		{"type": "string", "pattern": "^[a-z]+$", "minLength": float64(2)},
+	}
+
+	assert.Len(t, fields, len(expected))
+	for i, f := range fields {
+		assert.Equal(t, expected[i], getFieldSchema(f), f.name)
+	}
+}
+
+func TestGenerateReadOnly(t *testing.T) {
+	type up struct {
+		Host string
+	}
+
+	type readOnlyConfig struct {
+		ReadOnlyBackends []up
+		ReadOnlyPrimary  *up
+	}
+
+	assert.NoError(t, os.Setenv("READ_ONLY_BACKENDS_3_HOST", "d.local"))
+	defer func() {
+		assert.NoError(t, os.Unsetenv("READ_ONLY_BACKENDS_3_HOST"))
+	}()
+
+	tests := []struct {
+		name     string
+		generate func(config interface{}) error
+	}{
+		{"GenerateMarkdown", func(config interface{}) error { _, err := GenerateMarkdown(config); return err }},
+		{"GenerateEnvFile", func(config interface{}) error { _, err := GenerateEnvFile(config); return err }},
+		{"GenerateJSONSchema", func(config interface{}) error { _, err := GenerateJSONSchema(config); return err }},
+		{"Usage", func(config interface{}) error { _, err := Usage(config, SkipFlag()); return err }},
+		{"Dump", func(config interface{}) error { _, err := Dump(config, SkipFlag()); return err }},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c := &readOnlyConfig{
+				ReadOnlyBackends: []up{{Host: "a.local"}},
+			}
+
+			assert.NoError(t, tc.generate(c))
+
+			// The struct should be left untouched
+			assert.Equal(t, &readOnlyConfig{
+				ReadOnlyBackends: []up{{Host: "a.local"}},
+			}, c)
+		})
+	}
+}
+
+func TestGenerateElems(t *testing.T) {
+	type server struct {
+		Host string `json:"host" required:"true"`
+		Port int    `json:"port" default:"443"`
+	}
+
+	type elemsConfig struct {
+		Servers      []server           `json:"servers"`
+		ServersCount int                `json:"servers_count"`
+		Backends     map[string]*server `json:"backends"`
+	}
+
+	assert.NoError(t, os.Setenv("SERVERS_2_HOST", "c.local"))
+	assert.NoError(t, os.Setenv("BACKENDS_PRIMARY_HOST", "p.local"))
+	defer func() {
+		assert.NoError(t, os.Unsetenv("SERVERS_2_HOST"))
+		assert.NoError(t, os.Unsetenv("BACKENDS_PRIMARY_HOST"))
+	}()
+
+	// Elements in the struct and in the environment are ignored
+	c := &elemsConfig{
+		Servers: []server{{Host: "a.local"}},
+	}
+
+	t.Run("GenerateMarkdown", func(t *testing.T) {
+		out, err := GenerateMarkdown(c)
+		assert.NoError(t, err)
+
+		expected := "" +
+			"| Field | Type | Default | Flag | Environment Variable | File Environment Variable | Description |\n" +
+			"|-------|------|---------|------|----------------------|---------------------------|-------------|\n" +
+			"| `Servers[*].Host` | `string` |  | `-servers.*.host` | `SERVERS_*_HOST` | `SERVERS_*_HOST_FILE` |  |\n" +
+			"| `Servers[*].Port` | `int` | `443` | `-servers.*.port` | `SERVERS_*_PORT` | `SERVERS_*_PORT_FILE` |  |\n" +
+			"| `ServersCount` | `int` | `0` | `-servers.count` | `SERVERS_COUNT` | `SERVERS_COUNT_FILE` |  |\n" +
+			"| `Backends[*].Host` | `string` |  | `-backends.*.host` | `BACKENDS_*_HOST` | `BACKENDS_*_HOST_FILE` |  |\n" +
+			"| `Backends[*].Port` | `int` | `443` | `-backends.*.port` | `BACKENDS_*_PORT` | `BACKENDS_*_PORT_FILE` |  |\n"
+
+		assert.Equal(t, expected, out)
+	})
+
+	t.Run("GenerateEnvFile", func(t *testing.T) {
+		out, err := GenerateEnvFile(c)
+		assert.NoError(t, err)
+		assert.Equal(t, "# Type: int\nSERVERS_COUNT=0\n", out)
+	})
+
+	t.Run("GenerateJSONSchema", func(t *testing.T) {
+		out, err := GenerateJSONSchema(c)
+		assert.NoError(t, err)
+
+		elem := `{
+        "properties": {
+          "host": {
+            "type": "string"
+          },
+          "port": {
+            "default": 443,
+            "type": "integer"
+          }
+        },
+        "required": [
+          "host"
+        ],
+        "type": "object"
+      }`
+
+		expected := `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "properties": {
+    "backends": {
+      "additionalProperties": ` + elem + `,
+      "type": "object"
+    },
+    "servers": {
+      "items": ` + elem + `,
+      "type": "array"
+    },
+    "servers_count": {
+      "default": 0,
+      "type": "integer"
+    }
+  },
+  "type": "object"
+}
+`
+
+		assert.Equal(t, expected, out)
+	})
+}
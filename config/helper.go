@@ -5,7 +5,10 @@ import (
 	"os"
 	"reflect"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
 	"unicode"
 )
 
@@ -20,6 +23,95 @@ func (v flagValue) Set(string) error {
 	return nil
 }
 
+// fieldFlag implements the flag.Value interface for a field.
+// It validates values against the type of the field when flags are parsed.
+type fieldFlag struct {
+	r   *reader
+	f   fieldInfo
+	val string
+}
+
+func (v *fieldFlag) String() string {
+	if v == nil {
+		return ""
+	}
+	return v.val
+}
+
+func (v *fieldFlag) Set(val string) error {
+	v.r.protectValue(v.f, val)
+
+	// References to secrets are validated after they are resolved
+	if !v.r.isReference(val) {
+		if _, err := v.r.tryFieldValue(v.f, val); err != nil {
+			return err
+		}
+	}
+
+	v.val = val
+	return nil
+}
+
+// IsBoolFlag allows boolean flags to be set without a value (i.e. -enabled).
+func (v *fieldFlag) IsBoolFlag() bool {
+	t := v.f.value.Type()
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return t.Kind() == reflect.Bool
+}
+
+// getDefaultValue returns the default value of a field for documentation.
+// The value specified by the default tag takes precedence over the initial value of the field.
+func getDefaultValue(f fieldInfo) string {
+	if f.defaultVal != "" {
+		return f.defaultVal
+	}
+
+	// Zero values of structs (i.e. time.Time) are not meaningful defaults
+	if f.value.Kind() == reflect.Struct && f.value.IsZero() {
+		return ""
+	}
+
+	return formatValue(f.value, f.listSep, f.layout)
+}
+
+// secretWords are the words in field names that indicate sensitive values.
+var secretWords = []string{"password", "passwd", "secret", "token", "apikey", "privatekey", "credential"}
+
+// isSecret determines whether or not a field holds a sensitive value.
+// The secret tag takes precedence over detecting sensitive names (i.e. DBPassword or APIToken).
+// Names are matched by whole words, and only string fields are detected by name (i.e. PasswordMinLength or TokenTTL are not secrets).
+func isSecret(f reflect.StructField) bool {
+	if b, err := strconv.ParseBool(f.Tag.Get(tagSecret)); err == nil {
+		return b
+	}
+
+	t := f.Type
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.String {
+		return false
+	}
+
+	// Secret words can span multiple tokens (i.e. APIKey --> API, Key)
+	tokens := tokenize(f.Name)
+	for i := range tokens {
+		var word string
+		for _, token := range tokens[i:] {
+			word += strings.ToLower(token)
+			if slices.Contains(secretWords, word) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
 // tokenize breaks a field name into its tokens (generally words).
 //   UserID       -->  User, ID
 //   DatabaseURL  -->  Database, URL
@@ -94,14 +186,22 @@ func getFileEnvVarName(name string) string {
 	return result
 }
 
+// flagArgRegex matches the command-line arguments that are flags rather than values (i.e. -port but not -5).
+var flagArgRegex = regexp.MustCompile("^-{1,2}[A-Za-z].*")
+
 // getFlagValue returns the value set for a flag.
 //   - The flag name can start with - or --
 //   - The flag value can be separated by space or =
+//   - The flag name should exactly match (--port does not match --portal)
+//   - Arguments after the -- terminator are not flags
 func getFlagValue(flagName string) string {
-	flagRegex := regexp.MustCompile("-{1,2}" + flagName)
-	genericRegex := regexp.MustCompile("^-{1,2}[A-Za-z].*")
+	flagRegex := regexp.MustCompile("^-{1,2}" + regexp.QuoteMeta(flagName) + "(=|$)")
 
 	for i, arg := range os.Args {
+		if arg == "--" {
+			break
+		}
+
 		if flagRegex.MatchString(arg) {
 			if s := strings.Index(arg, "="); s > 0 {
 				return arg[s+1:]
@@ -109,7 +209,7 @@ func getFlagValue(flagName string) string {
 
 			if i+1 < len(os.Args) {
 				val := os.Args[i+1]
-				if !genericRegex.MatchString(val) {
+				if !flagArgRegex.MatchString(val) {
 					return val
 				}
 			}
@@ -121,6 +221,56 @@ func getFlagValue(flagName string) string {
 	return ""
 }
 
+// cloneStruct returns a copy of a struct in which nested structs, pointers to nested structs, and slices and maps of nested structs are copied too.
+// Locks (sync.Mutex and sync.RWMutex) are reset in the copy.
+// Other fields are shallow-copied since new values are never set on them in place.
+func cloneStruct(v reflect.Value) reflect.Value {
+	c := reflect.New(v.Type()).Elem()
+	c.Set(v)
+
+	for i := 0; i < c.NumField(); i++ {
+		f := c.Field(i)
+		if !f.CanSet() {
+			continue
+		}
+
+		switch {
+		case f.Type() == reflect.TypeOf(sync.Mutex{}) || f.Type() == reflect.TypeOf(sync.RWMutex{}):
+			f.Set(reflect.Zero(f.Type()))
+		case f.Kind() == reflect.Struct && !isTypeSupported(f.Type()):
+			f.Set(cloneStruct(f))
+		case f.Kind() == reflect.Ptr && !f.IsNil() && f.Type().Elem().Kind() == reflect.Struct && !isTypeSupported(f.Type()):
+			f.Set(cloneStruct(f.Elem()).Addr())
+		case (f.Kind() == reflect.Slice || f.Kind() == reflect.Map) && !f.IsNil():
+			if _, ok := getStructElem(f.Type()); ok {
+				f.Set(cloneElems(f))
+			}
+		}
+	}
+
+	return c
+}
+
+// copyStruct sets the fields of a struct to the fields of another struct of the same type.
+// Locks (sync.Mutex and sync.RWMutex) and unexported fields are left untouched, and nested structs are copied field by field.
+func copyStruct(dst, src reflect.Value) {
+	for i := 0; i < dst.NumField(); i++ {
+		f := dst.Field(i)
+		if !f.CanSet() {
+			continue
+		}
+
+		switch {
+		case f.Type() == reflect.TypeOf(sync.Mutex{}) || f.Type() == reflect.TypeOf(sync.RWMutex{}):
+			continue
+		case f.Kind() == reflect.Struct && !isTypeSupported(f.Type()):
+			copyStruct(f, src.Field(i))
+		default:
+			f.Set(src.Field(i))
+		}
+	}
+}
+
 func validateStruct(s interface{}) (reflect.Value, error) {
 	v := reflect.ValueOf(s) // reflect.Value --> v.Type(), v.Kind(), v.NumField()
 	t := reflect.TypeOf(s)  // reflect.Type --> t.Name(), t.Kind(), t.NumField()
@@ -142,6 +292,10 @@ func validateStruct(s interface{}) (reflect.Value, error) {
 }
 
 func isTypeSupported(t reflect.Type) bool {
+	if hasDecoder(t) {
+		return true
+	}
+
 	switch t.Kind() {
 	case reflect.String:
 		return true
@@ -153,11 +307,17 @@ func isTypeSupported(t reflect.Type) bool {
 		return true
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
 		return true
-	case reflect.Ptr, reflect.Slice:
+	case reflect.Ptr:
 		return isTypeSupported(t.Elem())
+	case reflect.Slice:
+		// A list of maps cannot be represented using a single separator
+		return t.Elem().Kind() != reflect.Map && isTypeSupported(t.Elem())
+	case reflect.Map:
+		return t.Key().Kind() == reflect.String && t.Elem().Kind() == reflect.String
 	case reflect.Struct:
 		return (t.PkgPath() == "net/url" && t.Name() == "URL") ||
-			(t.PkgPath() == "regexp" && t.Name() == "Regexp")
+			(t.PkgPath() == "regexp" && t.Name() == "Regexp") ||
+			(t.PkgPath() == "time" && t.Name() == "Time")
 	}
 
 	return false
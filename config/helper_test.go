@@ -6,6 +6,7 @@ import (
 	"os"
 	"reflect"
 	"regexp"
+	"sync"
 	"testing"
 	"time"
 
@@ -143,6 +144,13 @@ func TestGetFlagValue(t *testing.T) {
 		{[]string{"app", "--name-list=alice,bob"}, "name-list", "alice,bob"},
 		{[]string{"app", "-name-list", "alice,bob"}, "name-list", "alice,bob"},
 		{[]string{"app", "--name-list", "alice,bob"}, "name-list", "alice,bob"},
+
+		{[]string{"app", "--portal=8080"}, "port", ""},
+		{[]string{"app", "--portal", "8080", "--port", "9090"}, "port", "9090"},
+		{[]string{"app", "--import=true"}, "port", ""},
+		{[]string{"app", "--log-level=info"}, "log.level", ""},
+		{[]string{"app", "--log.level=info"}, "log.level", "info"},
+		{[]string{"app", "--", "--port=8080"}, "port", ""},
 	}
 
 	origArgs := os.Args
@@ -158,6 +166,70 @@ func TestGetFlagValue(t *testing.T) {
 	}
 }
 
+func TestFieldFlag(t *testing.T) {
+	var port uint16
+	var enabled *bool
+
+	tests := []struct {
+		name           string
+		f              fieldInfo
+		val            string
+		expectedError  string
+		expectedString string
+		expectedBool   bool
+	}{
+		{
+			name:           "Valid",
+			f:              fieldInfo{value: reflect.ValueOf(&port).Elem(), name: "Port"},
+			val:            "8080",
+			expectedString: "8080",
+			expectedBool:   false,
+		},
+		{
+			name:           "Invalid",
+			f:              fieldInfo{value: reflect.ValueOf(&port).Elem(), name: "Port"},
+			val:            "http",
+			expectedError:  `strconv.ParseUint: parsing "http": invalid syntax`,
+			expectedString: "",
+			expectedBool:   false,
+		},
+		{
+			name:           "BoolPointer",
+			f:              fieldInfo{value: reflect.ValueOf(&enabled).Elem(), name: "Enabled"},
+			val:            "true",
+			expectedString: "true",
+			expectedBool:   true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			v := &fieldFlag{
+				r: &reader{},
+				f: tc.f,
+			}
+
+			err := v.Set(tc.val)
+
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expectedError)
+			}
+
+			assert.Equal(t, tc.expectedString, v.String())
+			assert.Equal(t, tc.expectedBool, v.IsBoolFlag())
+		})
+	}
+
+	// The field itself should not be set
+	assert.Zero(t, port)
+	assert.Nil(t, enabled)
+
+	// The zero value is used by the flag package for printing defaults
+	assert.Equal(t, "", (*fieldFlag)(nil).String())
+}
+
 func TestValidateStruct(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -197,6 +269,8 @@ func TestValidateStruct(t *testing.T) {
 }
 
 func TestIsTypeSupported(t *testing.T) {
+	registerTestDecoders(t)
+
 	u, _ := url.Parse("service-1")
 	r := regexp.MustCompilePOSIX("[:digit:]")
 
@@ -222,6 +296,13 @@ func TestIsTypeSupported(t *testing.T) {
 		{"URL", *u, true},
 		{"Regexp", *r, true},
 		{"Duration", time.Second, true},
+		{"Time", time.Time{}, true},
+		{"StringMap", map[string]string{}, true},
+		{"IntMap", map[string]int{}, false},
+		{"Struct", struct{}{}, false},
+		{"TextUnmarshaler", byteSize(0), true},
+		{"FlagValue", logLevel(0), true},
+		{"RegisteredDecoder", endpoint{}, true},
 		{"StringPointer", ptr.String("content"), true},
 		{"BoolPointer", ptr.Bool(true), true},
 		{"Float32Pointer", ptr.Float32(3.1415), true},
@@ -239,6 +320,9 @@ func TestIsTypeSupported(t *testing.T) {
 		{"URLPointer", u, true},
 		{"RegexpPointer", r, true},
 		{"DurationPointer", ptr.Duration(time.Second), true},
+		{"TimePointer", &time.Time{}, true},
+		{"StringMapPointer", &map[string]string{}, true},
+		{"TextUnmarshalerPointer", new(byteSize), true},
 		{"StringSlice", []string{"content"}, true},
 		{"BoolSlice", []bool{true}, true},
 		{"Float32Slice", []float32{3.1415}, true},
@@ -256,6 +340,9 @@ func TestIsTypeSupported(t *testing.T) {
 		{"URLSlice", []url.URL{*u}, true},
 		{"RegexpSlice", []regexp.Regexp{*r}, true},
 		{"DurationSlice", []time.Duration{time.Second}, true},
+		{"TimeSlice", []time.Time{{}}, true},
+		{"StringMapSlice", []map[string]string{}, false},
+		{"RegisteredDecoderSlice", []endpoint{}, true},
 	}
 
 	for _, tc := range tests {
@@ -266,3 +353,109 @@ func TestIsTypeSupported(t *testing.T) {
 		})
 	}
 }
+
+func TestCloneStruct(t *testing.T) {
+	type nested struct {
+		Level string
+	}
+
+	type config struct {
+		sync.Mutex
+		Name    string
+		Tags    []string
+		Timeout *time.Duration
+		Nested  nested
+		Pointer *nested
+		Missing *nested
+		List    []nested
+		Map     map[string]*nested
+		private nested
+	}
+
+	orig := &config{
+		Name:    "app",
+		Tags:    []string{"a", "b"},
+		Timeout: ptr.Duration(time.Second),
+		Nested:  nested{Level: "info"},
+		Pointer: &nested{Level: "debug"},
+		List:    []nested{{Level: "info"}},
+		Map:     map[string]*nested{"primary": {Level: "info"}},
+		private: nested{Level: "warn"},
+	}
+
+	orig.Lock()
+	defer orig.Unlock()
+
+	c := cloneStruct(reflect.ValueOf(orig).Elem()).Addr().Interface().(*config)
+
+	assert.Equal(t, "app", c.Name)
+	assert.Equal(t, []string{"a", "b"}, c.Tags)
+	assert.Equal(t, time.Second, *c.Timeout)
+	assert.Equal(t, nested{Level: "info"}, c.Nested)
+	assert.Equal(t, &nested{Level: "debug"}, c.Pointer)
+	assert.Nil(t, c.Missing)
+	assert.Equal(t, nested{Level: "warn"}, c.private)
+
+	// The lock should be reset in the copy
+	assert.True(t, c.TryLock())
+
+	// Nested structs should not be shared with the original struct
+	orig.Nested.Level = "error"
+	orig.Pointer.Level = "error"
+	assert.Equal(t, "info", c.Nested.Level)
+	assert.Equal(t, "debug", c.Pointer.Level)
+
+	// Slices and maps of nested structs should not be shared with the original struct
+	orig.List[0].Level = "error"
+	orig.Map["primary"].Level = "error"
+	assert.Equal(t, "info", c.List[0].Level)
+	assert.Equal(t, "info", c.Map["primary"].Level)
+}
+
+func TestIsSecret(t *testing.T) {
+	type fields struct {
+		Name         string
+		Password     string
+		DBPassword   string
+		APIToken     string
+		ClientSecret string
+		PrivateKey   string
+		APIKey       string
+		Tagged       string        `secret:"true"`
+		TokenTTL     time.Duration `secret:"false"`
+
+		PasswordMinLength int
+		SecretsEnabled    bool
+		TokenizerName     string
+		PasswordPtr       *string
+		TaggedDuration    time.Duration `secret:"true"`
+	}
+
+	tests := []struct {
+		field    string
+		expected bool
+	}{
+		{"Name", false},
+		{"Password", true},
+		{"DBPassword", true},
+		{"APIToken", true},
+		{"ClientSecret", true},
+		{"PrivateKey", true},
+		{"Tagged", true},
+		{"TokenTTL", false},
+		{"APIKey", true},
+		{"PasswordMinLength", false},
+		{"SecretsEnabled", false},
+		{"TokenizerName", false},
+		{"PasswordPtr", true},
+		{"TaggedDuration", true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.field, func(t *testing.T) {
+			f, ok := reflect.TypeOf(fields{}).FieldByName(tc.field)
+			assert.True(t, ok)
+			assert.Equal(t, tc.expected, isSecret(f))
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package config
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// Live holds the latest values of a config struct and reloads them when configuration files change.
+// Instead of setting new values on a struct in place, every reload builds a new copy of the struct and publishes it atomically.
+// Readers get a consistent snapshot of the struct without any locking, and all values from one reload appear together.
+type Live[T any] struct {
+	mu      sync.Mutex
+	ptr     atomic.Pointer[T]
+	watcher *Watcher
+}
+
+// NewLive reads values for a struct like Pick and returns a Live holding a copy of the struct.
+// The given struct is used for default values and it is not changed after NewLive returns.
+// Live keeps watching configuration files until the given context is cancelled or Close is called.
+// The struct does not need to implement sync.Locker and snapshots returned by Load should not be modified.
+func NewLive[T any](ctx context.Context, config *T, opts ...Option) (*Live[T], error) {
+	l := new(Live[T])
+
+	if _, err := validateStruct(config); err != nil {
+		return nil, err
+	}
+
+	// The working copy is only accessed by the watcher
+	work := cloneStruct(reflect.ValueOf(config).Elem()).Addr().Interface().(*T)
+
+	w, err := prepareWatch(work, &l.mu, nil, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	l.watcher = w
+	l.ptr.Store(snapshot(work).(*T))
+
+	// The first listener publishes the new snapshot before other listeners are called
+	w.addListener(func(_, new interface{}) {
+		l.ptr.Store(new.(*T))
+	})
+
+	if err := w.start(ctx); err != nil {
+		return nil, err
+	}
+
+	return l, nil
+}
+
+// Load returns the latest snapshot of the config struct.
+func (l *Live[T]) Load() *T {
+	return l.ptr.Load()
+}
+
+// Watcher returns the underlying watcher for registering listeners using OnChange and OnUpdate.
+func (l *Live[T]) Watcher() *Watcher {
+	return l.watcher
+}
+
+// Close stops watching and waits until the watcher is fully stopped.
+func (l *Live[T]) Close() {
+	l.watcher.Close()
+}
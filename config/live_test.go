@@ -0,0 +1,106 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type liveConfig struct {
+	LiveLevel  string
+	LiveFormat string
+	LiveServer struct {
+		Port int
+	}
+}
+
+func TestNewLive(t *testing.T) {
+	dir := t.TempDir()
+	levelPath := filepath.Join(dir, "level")
+	formatPath := filepath.Join(dir, "format")
+	assert.NoError(t, os.WriteFile(levelPath, []byte("info"), 0644))
+	assert.NoError(t, os.WriteFile(formatPath, []byte("json"), 0644))
+
+	assert.NoError(t, os.Setenv("LIVE_LEVEL_FILE", levelPath))
+	assert.NoError(t, os.Setenv("LIVE_FORMAT_FILE", formatPath))
+
+	defer func() {
+		assert.NoError(t, os.Unsetenv("LIVE_LEVEL_FILE"))
+		assert.NoError(t, os.Unsetenv("LIVE_FORMAT_FILE"))
+	}()
+
+	origArgs := os.Args
+	defer func() {
+		os.Args = origArgs
+	}()
+
+	os.Args = []string{"app"}
+
+	t.Run("InvalidConfig", func(t *testing.T) {
+		l, err := NewLive(context.Background(), new(int))
+		assert.EqualError(t, err, "a non-struct type is passed")
+		assert.Nil(t, l)
+	})
+
+	t.Run("Reload", func(t *testing.T) {
+		defaults := new(liveConfig)
+		defaults.LiveServer.Port = 8080
+
+		// Polling reads both files at once
+		l, err := NewLive(context.Background(), defaults, PollInterval(50*time.Millisecond))
+		assert.NoError(t, err)
+		defer l.Close()
+
+		first := l.Load()
+		assert.Equal(t, "info", first.LiveLevel)
+		assert.Equal(t, "json", first.LiveFormat)
+		assert.Equal(t, 8080, first.LiveServer.Port)
+
+		// The given struct should not be changed
+		assert.Empty(t, defaults.LiveLevel)
+		assert.Empty(t, defaults.LiveFormat)
+
+		updates := make(chan *liveConfig, 10)
+		err = OnUpdate(l.Watcher(), func(_, new *liveConfig) {
+			// The new snapshot should be already published
+			assert.Same(t, new, l.Load())
+			updates <- new
+		})
+		assert.NoError(t, err)
+
+		// Write the new files and swap them in together
+		assert.NoError(t, os.WriteFile(levelPath+".tmp", []byte("debug"), 0644))
+		assert.NoError(t, os.WriteFile(formatPath+".tmp", []byte("text"), 0644))
+		assert.NoError(t, os.Rename(levelPath+".tmp", levelPath))
+		assert.NoError(t, os.Rename(formatPath+".tmp", formatPath))
+
+		var latest *liveConfig
+		assert.Eventually(t, func() bool {
+			latest = l.Load()
+			return latest.LiveLevel == "debug" && latest.LiveFormat == "text"
+		}, 2*time.Second, 10*time.Millisecond)
+
+		assert.Equal(t, 8080, latest.LiveServer.Port)
+		assert.NotEmpty(t, updates)
+
+		// Previous snapshots should not be changed
+		assert.Equal(t, "info", first.LiveLevel)
+		assert.Equal(t, "json", first.LiveFormat)
+	})
+
+	t.Run("Close", func(t *testing.T) {
+		l, err := NewLive(context.Background(), new(liveConfig))
+		assert.NoError(t, err)
+
+		l.Close()
+		<-l.Watcher().Done()
+
+		assert.NoError(t, os.WriteFile(levelPath, []byte("warn"), 0644))
+		time.Sleep(50 * time.Millisecond)
+		assert.Equal(t, "debug", l.Load().LiveLevel)
+	})
+}
@@ -1,5 +1,12 @@
 package config
 
+import (
+	"flag"
+	"os"
+	"syscall"
+	"time"
+)
+
 // Option sets optional parameters for reader.
 type Option func(*reader)
 
@@ -81,3 +88,160 @@ func Telepresence() Option {
 		c.telepresence = true
 	}
 }
+
+// FromFile is the option for reading values from a structured configuration document.
+// The document format is determined by the file extension and can be YAML (.yaml, .yml), JSON (.json), or TOML (.toml).
+// Values read from the document have lower priority than command-line flags, environment variables, and configuration files.
+// You can specify a custom key for each field using `yaml`, `json`, or `toml` struct tags.
+func FromFile(path string) Option {
+	return func(c *reader) {
+		c.docPath = path
+	}
+}
+
+// Lenient is the option for ignoring values that cannot be set on their fields.
+// By default, an error listing all such values is returned.
+// When this option is set, such fields keep their default values and the errors are only logged.
+func Lenient() Option {
+	return func(c *reader) {
+		c.lenient = true
+	}
+}
+
+// WithFlagSet is the option for parsing command-line flags using a flag set instead of scanning os.Args.
+// Typed flags for all fields are registered on the flag set and the arguments (without the program name) are parsed using it.
+// Values of flags are validated against the types of their fields at parse time and the global flag.CommandLine is left untouched.
+// If the flag set is already parsed, the values of the flags already set are used.
+// If the Help option is set, the usage function of the flag set is replaced for printing the usage of all fields (see Usage).
+func WithFlagSet(fs *flag.FlagSet, args []string) Option {
+	return func(c *reader) {
+		c.flagSet = fs
+		c.flagArgs = args
+	}
+}
+
+// WatchDir is the option for watching the directories of configuration files instead of the files themselves.
+// Symlinks are resolved and atomic renames and symlink swaps (such as the ..data symlink in Kubernetes volumes) are detected.
+func WatchDir() Option {
+	return func(c *reader) {
+		c.watchDir = true
+	}
+}
+
+// PollInterval is the option for polling configuration files at an interval instead of watching them.
+// It can be used on file systems where watching files is not reliable (e.g. overlay file systems and NFS).
+func PollInterval(interval time.Duration) Option {
+	return func(c *reader) {
+		c.pollInterval = interval
+	}
+}
+
+// WithSource is the option for reading values from an additional source (e.g. a remote configuration server).
+// Sources are read in the order they are added after command-line flags, environment variables, and configuration files,
+// and before the configuration document and default values.
+// When watching, sources implementing the WatchableSource interface are watched for changes too.
+func WithSource(src Source) Option {
+	return func(c *reader) {
+		c.sources = append(c.sources, src)
+	}
+}
+
+// ResolveSecrets is the option for resolving values that reference secrets (i.e. file:///run/secrets/db or vault://kv/data/db#password).
+// A value in the form of scheme://... is resolved using the resolver registered for its scheme (see RegisterResolver).
+// Resolvers for the file and env schemes are registered by default.
+// Resolved values are masked in debugging logs.
+func ResolveSecrets() Option {
+	return func(c *reader) {
+		c.resolveSecrets = true
+	}
+}
+
+// WithReport is the option for recording how the value of each field is read in a report.
+// The report includes the names of each field for every source, the source from which the value is read, and the file path if any.
+// When watching, the report is updated as new values are set on fields.
+func WithReport(report *Report) Option {
+	return func(c *reader) {
+		c.report = report
+	}
+}
+
+// WithEnvFile is the option for reading environment variables from one or more .env files before reading values.
+// A variable in a file overrides the same variable in the files before it,
+// and variables set in the process environment override the variables in all files.
+// You can also set this option using CONFIG_ENV_FILE environment variable to a comma-separated list of paths.
+func WithEnvFile(paths ...string) Option {
+	return func(c *reader) {
+		c.envFiles = append(c.envFiles, paths...)
+	}
+}
+
+// ExpandEnv is the option for expanding references to environment variables in values read for all fields.
+// ${VAR} is replaced with the value of VAR, ${VAR:-default} falls back to default if VAR is not set or empty, and $$ is replaced with $.
+// Variables are looked up in the process environment and the .env files (see WithEnvFile option).
+// You can also enable this option by setting CONFIG_EXPAND_ENV environment variable to true.
+func ExpandEnv() Option {
+	return func(c *reader) {
+		c.expandEnv = true
+	}
+}
+
+// Profile is the option for layering the values of a profile (i.e. dev, staging, or prod) over the base values.
+// For every field, the environment variable prefixed with the profile name in upper case (i.e. STAGING_DB_URL) is read first
+// and the unprefixed environment variable (i.e. DB_URL) is read if the prefixed one is not set.
+// The same applies to file environment variables (i.e. STAGING_DB_URL_FILE).
+// The profile-specific configuration document (i.e. config.staging.yaml for config.yaml) and .env files (i.e. .env.staging for .env)
+// are also read if they exist, and their values take precedence over the values in the base files.
+// You can also set this option using CONFIG_PROFILE environment variable.
+func Profile(name string) Option {
+	return func(c *reader) {
+		c.profile = name
+	}
+}
+
+// Help is the option for printing the usage of all fields (see Usage) when the -help (or -h) flag is passed on the command line.
+// Instead of exiting, Pick and Watch return ErrHelp, so the caller can decide what to do.
+// Help flags defined by the application are left to the application.
+func Help() Option {
+	return func(c *reader) {
+		c.help = true
+	}
+}
+
+// PrintConfig is the option for defining the -print-config flag.
+// If the flag is passed, the effective configuration is printed with secrets redacted (see Dump) and the program exits.
+// If reading or validating the configuration fails, nothing is printed and the errors are returned instead.
+func PrintConfig() Option {
+	return func(c *reader) {
+		c.printConfig = true
+	}
+}
+
+// ReloadOnSignal is the option for reloading the values of all fields from all sources when watching and a signal is received (see Watcher.Reload).
+// If no signal is given, SIGHUP is used.
+func ReloadOnSignal(sigs ...os.Signal) Option {
+	if len(sigs) == 0 {
+		sigs = []os.Signal{syscall.SIGHUP}
+	}
+
+	return func(c *reader) {
+		c.reloadSignals = append(c.reloadSignals, sigs...)
+	}
+}
+
+// Strict is the option for reporting the environment variables and the flags that match no field (i.e. DATABSE_URL instead of DATABASE_URL).
+// Environment variables are only checked if they have the prefix set by PrefixEnv or PrefixFileEnv option.
+// Each unknown name is reported as an *UnknownError along with the closest known name if any.
+// You can also enable this option by setting CONFIG_STRICT environment variable to true.
+func Strict() Option {
+	return func(c *reader) {
+		c.strict = true
+	}
+}
+
+// WithLogger is the option for printing debugging information and warnings (i.e. using deprecated names) using a custom logger.
+// By default, the standard logger of the log package is used.
+func WithLogger(logger Logger) Option {
+	return func(c *reader) {
+		c.logger = logger
+	}
+}
@@ -1,7 +1,14 @@
 package config
 
 import (
+	"flag"
+	"io"
+	"log"
+	"os"
+	"reflect"
+	"syscall"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -104,3 +111,220 @@ func TestTelepresence(t *testing.T) {
 
 	assert.Equal(t, expected, r)
 }
+
+func TestFromFile(t *testing.T) {
+	r := new(reader)
+	FromFile("config.yaml")(r)
+
+	expected := &reader{
+		docPath: "config.yaml",
+	}
+
+	assert.Equal(t, expected, r)
+}
+
+func TestLenient(t *testing.T) {
+	r := new(reader)
+	Lenient()(r)
+
+	expected := &reader{
+		lenient: true,
+	}
+
+	assert.Equal(t, expected, r)
+}
+
+func TestWithFlagSet(t *testing.T) {
+	fs := flag.NewFlagSet("app", flag.ContinueOnError)
+	args := []string{"-log.level=debug"}
+
+	r := new(reader)
+	WithFlagSet(fs, args)(r)
+
+	expected := &reader{
+		flagSet:  fs,
+		flagArgs: args,
+	}
+
+	assert.Equal(t, expected, r)
+}
+
+func TestWatchDir(t *testing.T) {
+	r := new(reader)
+	WatchDir()(r)
+
+	expected := &reader{
+		watchDir: true,
+	}
+
+	assert.Equal(t, expected, r)
+}
+
+func TestPollInterval(t *testing.T) {
+	r := new(reader)
+	PollInterval(10 * time.Second)(r)
+
+	expected := &reader{
+		pollInterval: 10 * time.Second,
+	}
+
+	assert.Equal(t, expected, r)
+}
+
+func TestWithSource(t *testing.T) {
+	src := mapSource{"log.level": "debug"}
+
+	r := new(reader)
+	WithSource(src)(r)
+
+	expected := &reader{
+		sources: []Source{src},
+	}
+
+	assert.Equal(t, expected, r)
+}
+
+func TestResolveSecrets(t *testing.T) {
+	r := new(reader)
+	ResolveSecrets()(r)
+
+	expected := &reader{
+		resolveSecrets: true,
+	}
+
+	assert.Equal(t, expected, r)
+}
+
+func TestWithEnvFile(t *testing.T) {
+	r := new(reader)
+	WithEnvFile(".env", ".env.local")(r)
+
+	expected := &reader{
+		envFiles: []string{".env", ".env.local"},
+	}
+
+	assert.Equal(t, expected, r)
+}
+
+func TestExpandEnv(t *testing.T) {
+	r := new(reader)
+	ExpandEnv()(r)
+
+	expected := &reader{
+		expandEnv: true,
+	}
+
+	assert.Equal(t, expected, r)
+}
+
+func TestProfile(t *testing.T) {
+	r := new(reader)
+	Profile("staging")(r)
+
+	expected := &reader{
+		profile: "staging",
+	}
+
+	assert.Equal(t, expected, r)
+}
+
+func TestHelp(t *testing.T) {
+	r := new(reader)
+	Help()(r)
+
+	expected := &reader{
+		help: true,
+	}
+
+	assert.Equal(t, expected, r)
+}
+
+func TestPrintConfig(t *testing.T) {
+	r := new(reader)
+	PrintConfig()(r)
+
+	expected := &reader{
+		printConfig: true,
+	}
+
+	assert.Equal(t, expected, r)
+}
+
+func TestReloadOnSignal(t *testing.T) {
+	tests := []struct {
+		name           string
+		sigs           []os.Signal
+		expectedReader *reader
+	}{
+		{
+			name: "Default",
+			sigs: nil,
+			expectedReader: &reader{
+				reloadSignals: []os.Signal{syscall.SIGHUP},
+			},
+		},
+		{
+			name: "Signals",
+			sigs: []os.Signal{syscall.SIGINT, syscall.SIGTERM},
+			expectedReader: &reader{
+				reloadSignals: []os.Signal{syscall.SIGINT, syscall.SIGTERM},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := new(reader)
+			ReloadOnSignal(tc.sigs...)(r)
+
+			assert.Equal(t, tc.expectedReader, r)
+		})
+	}
+}
+
+func TestWithValidator(t *testing.T) {
+	r := new(reader)
+	WithValidator(validatePool)(r)
+
+	assert.Len(t, r.validators, 1)
+	assert.Equal(t, reflect.TypeOf(&pooled{}), r.validators[0].typ)
+	assert.NoError(t, r.validators[0].fn(nil, &pooled{PoolMin: 1, PoolMax: 2}))
+	assert.EqualError(t, r.validators[0].fn(nil, &pooled{PoolMin: 2, PoolMax: 1}), "max pool size is below min pool size")
+}
+
+func TestStrict(t *testing.T) {
+	r := new(reader)
+	Strict()(r)
+
+	expected := &reader{
+		strict: true,
+	}
+
+	assert.Equal(t, expected, r)
+}
+
+func TestWithLogger(t *testing.T) {
+	logger := log.New(io.Discard, "", 0)
+
+	r := new(reader)
+	WithLogger(logger)(r)
+
+	expected := &reader{
+		logger: logger,
+	}
+
+	assert.Equal(t, expected, r)
+}
+
+func TestWithReport(t *testing.T) {
+	report := new(Report)
+
+	r := new(reader)
+	WithReport(report)(r)
+
+	expected := &reader{
+		report: report,
+	}
+
+	assert.Equal(t, expected, r)
+}
@@ -0,0 +1,66 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// profileEnvNames returns the names of an environment variable to be looked up in order.
+// If a profile is set, the name prefixed with the profile (i.e. STAGING_DB_URL) comes first.
+func (r *reader) profileEnvNames(name string) []string {
+	if r.profile == "" {
+		return []string{name}
+	}
+
+	prefix := strings.ToUpper(r.profile) + "_"
+
+	return []string{prefix + name, name}
+}
+
+// lookupProfileEnv returns the value and the name of the first environment variable set for a field.
+func (r *reader) lookupProfileEnv(name string) (string, string) {
+	for _, n := range r.profileEnvNames(name) {
+		if val, ok := r.lookupEnv(n); ok && val != "" {
+			return val, n
+		}
+	}
+
+	return "", name
+}
+
+// profilePath returns the path to the profile-specific version of a file.
+//
+//	config.yaml  -->  config.staging.yaml
+//	.env         -->  .env.staging
+func profilePath(path, profile string) string {
+	ext := filepath.Ext(path)
+	if ext == "" || ext == filepath.Base(path) {
+		return path + "." + profile
+	}
+
+	return strings.TrimSuffix(path, ext) + "." + profile + ext
+}
+
+// fileExists determines whether or not a regular file exists at a path.
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular()
+}
+
+// withProfilePaths returns a list of files with the profile-specific version of each file (if exists) after it.
+func (r *reader) withProfilePaths(paths []string) []string {
+	if r.profile == "" {
+		return paths
+	}
+
+	res := []string{}
+	for _, path := range paths {
+		res = append(res, path)
+		if pp := profilePath(path, r.profile); fileExists(pp) {
+			res = append(res, pp)
+		}
+	}
+
+	return res
+}
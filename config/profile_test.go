@@ -0,0 +1,140 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProfilePath(t *testing.T) {
+	tests := []struct {
+		name         string
+		path         string
+		profile      string
+		expectedPath string
+	}{
+		{"Document", "config.yaml", "staging", "config.staging.yaml"},
+		{"DocumentInDir", "/etc/app/config.json", "prod", "/etc/app/config.prod.json"},
+		{"EnvFile", ".env", "dev", ".env.dev"},
+		{"EnvFileInDir", "app/.env", "dev", "app/.env.dev"},
+		{"NoExtension", "config", "dev", "config.dev"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedPath, profilePath(tc.path, tc.profile))
+		})
+	}
+}
+
+func TestReaderProfileEnvNames(t *testing.T) {
+	tests := []struct {
+		name          string
+		r             *reader
+		envName       string
+		expectedNames []string
+	}{
+		{"NoProfile", &reader{}, "DB_URL", []string{"DB_URL"}},
+		{"WithProfile", &reader{profile: "staging"}, "DB_URL", []string{"STAGING_DB_URL", "DB_URL"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedNames, tc.r.profileEnvNames(tc.envName))
+		})
+	}
+}
+
+func TestPickWithProfile(t *testing.T) {
+	type profileConfig struct {
+		ProfileName  string
+		ProfileURL   string
+		ProfileToken string
+		ProfileLevel string
+		ProfilePort  int
+	}
+
+	dir := t.TempDir()
+	docPath := filepath.Join(dir, "config.yaml")
+	tokenPath := filepath.Join(dir, "token")
+	stagingTokenPath := filepath.Join(dir, "staging-token")
+
+	assert.NoError(t, os.WriteFile(docPath, []byte("profile_level: info\nprofile_port: 8080\n"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "config.staging.yaml"), []byte("profile_level: warn\n"), 0644))
+	assert.NoError(t, os.WriteFile(tokenPath, []byte("base-token"), 0644))
+	assert.NoError(t, os.WriteFile(stagingTokenPath, []byte("staging-token"), 0644))
+
+	env := map[string]string{
+		"PROFILE_NAME":               "app",
+		"PROFILE_URL":                "postgres://localhost",
+		"STAGING_PROFILE_URL":        "postgres://staging",
+		"PROFILE_TOKEN_FILE":         tokenPath,
+		"STAGING_PROFILE_TOKEN_FILE": stagingTokenPath,
+	}
+
+	for name, val := range env {
+		assert.NoError(t, os.Setenv(name, val))
+	}
+
+	defer func() {
+		for name := range env {
+			assert.NoError(t, os.Unsetenv(name))
+		}
+	}()
+
+	t.Run("NoProfile", func(t *testing.T) {
+		c := new(profileConfig)
+		err := Pick(c, SkipFlag(), FromFile(docPath))
+		assert.NoError(t, err)
+
+		assert.Equal(t, &profileConfig{
+			ProfileName:  "app",
+			ProfileURL:   "postgres://localhost",
+			ProfileToken: "base-token",
+			ProfileLevel: "info",
+			ProfilePort:  8080,
+		}, c)
+	})
+
+	t.Run("WithProfile", func(t *testing.T) {
+		c := new(profileConfig)
+		err := Pick(c, SkipFlag(), FromFile(docPath), Profile("staging"))
+		assert.NoError(t, err)
+
+		assert.Equal(t, &profileConfig{
+			ProfileName:  "app",
+			ProfileURL:   "postgres://staging",
+			ProfileToken: "staging-token",
+			ProfileLevel: "warn",
+			ProfilePort:  8080,
+		}, c)
+	})
+
+	t.Run("NoProfileDocument", func(t *testing.T) {
+		c := new(profileConfig)
+		err := Pick(c, SkipFlag(), FromFile(docPath), Profile("prod"))
+		assert.NoError(t, err)
+
+		assert.Equal(t, &profileConfig{
+			ProfileName:  "app",
+			ProfileURL:   "postgres://localhost",
+			ProfileToken: "base-token",
+			ProfileLevel: "info",
+			ProfilePort:  8080,
+		}, c)
+	})
+
+	t.Run("ProfileEnvFile", func(t *testing.T) {
+		envPath := filepath.Join(dir, ".env")
+		assert.NoError(t, os.WriteFile(envPath, []byte("PROFILE_LEVEL=debug\n"), 0644))
+		assert.NoError(t, os.WriteFile(envPath+".dev", []byte("PROFILE_LEVEL=error\n"), 0644))
+
+		c := new(profileConfig)
+		err := Pick(c, SkipFlag(), WithEnvFile(envPath), Profile("dev"))
+		assert.NoError(t, err)
+
+		assert.Equal(t, "error", c.ProfileLevel)
+	})
+}
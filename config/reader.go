@@ -5,33 +5,78 @@ import (
 	"fmt"
 	"log"
 	"os"
-	"path/filepath"
 	"reflect"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
+
+	multierror "github.com/hashicorp/go-multierror"
 )
 
-// fieldInfo has all the information for setting a struct field later.
+// fieldInfo has all the information for reading and setting a struct field.
 type fieldInfo struct {
-	value   reflect.Value
-	name    string
-	listSep string
+	value       reflect.Value
+	name        string
+	flagName    string
+	envName     string
+	fileEnvName string
+	docKeys     []string
+	listSep     string
+	layout      string
+	defaultVal  string
+	validation  validation
+	secret      bool
+	desc        string
+	aliases     []string
+	deprecated  string
+	// store is called after setting a value on a field of a struct in a map (map elements are not addressable).
+	store func()
 }
 
 // reader controls how configuration values are read.
 type reader struct {
-	debug         uint
-	listSep       string
-	skipFlag      bool
-	skipEnv       bool
-	skipFileEnv   bool
-	prefixFlag    string
-	prefixEnv     string
-	prefixFileEnv string
-	telepresence  bool
-
-	subscribers   []chan Update
+	debug          uint
+	listSep        string
+	skipFlag       bool
+	skipEnv        bool
+	skipFileEnv    bool
+	prefixFlag     string
+	prefixEnv      string
+	prefixFileEnv  string
+	telepresence   bool
+	docPath        string
+	lenient        bool
+	flagSet        *flag.FlagSet
+	flagArgs       []string
+	watchDir       bool
+	pollInterval   time.Duration
+	sources        []Source
+	resolveSecrets bool
+	report         *Report
+	envFiles       []string
+	expandEnv      bool
+	profile        string
+	help           bool
+	printConfig    bool
+	reloadSignals  []os.Signal
+	validators     []validator
+	strict         bool
+	logger         Logger
+	placeholders   bool
+
+	doc           *document
+	profileDoc    *document
+	envVars       map[string]string
+	flagValues    map[string]string
+	subscribers   []*subscriber
 	filesToFields map[string]fieldInfo
+	fields        []fieldInfo
+	secrets       *secretSet
+	holding       bool
+	held          []Update
+	warned        map[string]bool
+	failed        map[string]bool
 }
 
 // readerFromEnv creates a new reader with defaults and with options read from environment variables.
@@ -75,16 +120,64 @@ func readerFromEnv() *reader {
 		telepresence, _ = strconv.ParseBool(str)
 	}
 
+	docPath := os.Getenv(envFromFile)
+
+	var lenient bool
+	if str := os.Getenv(envLenient); str != "" {
+		lenient, _ = strconv.ParseBool(str)
+	}
+
+	var watchDir bool
+	if str := os.Getenv(envWatchDir); str != "" {
+		watchDir, _ = strconv.ParseBool(str)
+	}
+
+	var pollInterval time.Duration
+	if str := os.Getenv(envPollInterval); str != "" {
+		pollInterval, _ = time.ParseDuration(str)
+	}
+
+	var resolveSecrets bool
+	if str := os.Getenv(envResolveSecrets); str != "" {
+		resolveSecrets, _ = strconv.ParseBool(str)
+	}
+
+	var envFiles []string
+	if str := os.Getenv(envEnvFile); str != "" {
+		envFiles = strings.Split(str, ",")
+	}
+
+	var expandEnv bool
+	if str := os.Getenv(envExpandEnv); str != "" {
+		expandEnv, _ = strconv.ParseBool(str)
+	}
+
+	profile := os.Getenv(envProfile)
+
+	var strict bool
+	if str := os.Getenv(envStrict); str != "" {
+		strict, _ = strconv.ParseBool(str)
+	}
+
 	return &reader{
-		debug:         debug,
-		listSep:       listSep,
-		skipFlag:      skipFlag,
-		skipEnv:       skipEnv,
-		skipFileEnv:   skipFileEnv,
-		prefixFlag:    prefixFlag,
-		prefixEnv:     prefixEnv,
-		prefixFileEnv: prefixFileEnv,
-		telepresence:  telepresence,
+		debug:          debug,
+		listSep:        listSep,
+		skipFlag:       skipFlag,
+		skipEnv:        skipEnv,
+		skipFileEnv:    skipFileEnv,
+		prefixFlag:     prefixFlag,
+		prefixEnv:      prefixEnv,
+		prefixFileEnv:  prefixFileEnv,
+		telepresence:   telepresence,
+		docPath:        docPath,
+		lenient:        lenient,
+		watchDir:       watchDir,
+		pollInterval:   pollInterval,
+		resolveSecrets: resolveSecrets,
+		envFiles:       envFiles,
+		expandEnv:      expandEnv,
+		profile:        profile,
+		strict:         strict,
 
 		subscribers:   nil,
 		filesToFields: map[string]fieldInfo{},
@@ -132,6 +225,78 @@ func (r *reader) String() string {
 		strs = append(strs, "Telepresence")
 	}
 
+	if r.docPath != "" {
+		strs = append(strs, fmt.Sprintf("FromFile<%s>", r.docPath))
+	}
+
+	if r.lenient {
+		strs = append(strs, "Lenient")
+	}
+
+	if r.flagSet != nil {
+		strs = append(strs, fmt.Sprintf("FlagSet<%s>", r.flagSet.Name()))
+	}
+
+	if r.watchDir {
+		strs = append(strs, "WatchDir")
+	}
+
+	if r.pollInterval > 0 {
+		strs = append(strs, fmt.Sprintf("PollInterval<%s>", r.pollInterval))
+	}
+
+	if len(r.sources) > 0 {
+		strs = append(strs, fmt.Sprintf("Sources<%d>", len(r.sources)))
+	}
+
+	if r.resolveSecrets {
+		strs = append(strs, "ResolveSecrets")
+	}
+
+	if r.report != nil {
+		strs = append(strs, "Report")
+	}
+
+	if len(r.envFiles) > 0 {
+		strs = append(strs, fmt.Sprintf("EnvFiles<%s>", strings.Join(r.envFiles, ",")))
+	}
+
+	if r.expandEnv {
+		strs = append(strs, "ExpandEnv")
+	}
+
+	if r.profile != "" {
+		strs = append(strs, fmt.Sprintf("Profile<%s>", r.profile))
+	}
+
+	if r.help {
+		strs = append(strs, "Help")
+	}
+
+	if r.printConfig {
+		strs = append(strs, "PrintConfig")
+	}
+
+	if len(r.reloadSignals) > 0 {
+		names := make([]string, len(r.reloadSignals))
+		for i, sig := range r.reloadSignals {
+			names[i] = sig.String()
+		}
+		strs = append(strs, fmt.Sprintf("ReloadOnSignal<%s>", strings.Join(names, ",")))
+	}
+
+	if len(r.validators) > 0 {
+		strs = append(strs, fmt.Sprintf("Validators<%d>", len(r.validators)))
+	}
+
+	if r.strict {
+		strs = append(strs, "Strict")
+	}
+
+	if r.logger != nil {
+		strs = append(strs, "Logger")
+	}
+
 	if len(r.subscribers) > 0 {
 		strs = append(strs, fmt.Sprintf("Subscribers<%d>", len(r.subscribers)))
 	}
@@ -141,60 +306,127 @@ func (r *reader) String() string {
 
 func (r *reader) log(verbosity uint, msg string, args ...interface{}) {
 	if verbosity <= r.debug {
-		log.Printf(msg+"\n", args...)
+		r.print(msg, args...)
 	}
 }
 
+// warn prints a warning regardless of the debug verbosity level.
+func (r *reader) warn(msg string, args ...interface{}) {
+	r.print("WARNING: "+msg, args...)
+}
+
+// print prints a message using the logger specified by the WithLogger option or the standard logger.
+func (r *reader) print(msg string, args ...interface{}) {
+	msg = fmt.Sprintf(msg, args...)
+
+	if r.logger != nil {
+		r.logger.Printf("%s", msg)
+		return
+	}
+
+	log.Print(msg + "\n")
+}
+
+// loadDocument reads the configuration document if one is specified.
+func (r *reader) loadDocument() error {
+	if r.docPath == "" {
+		return nil
+	}
+
+	r.log(2, "Reading configuration document %s ...", r.docPath)
+
+	doc, err := readDocument(r.docPath)
+	if err != nil {
+		return err
+	}
+
+	// The profile-specific document is optional
+	var profileDoc *document
+	if r.profile != "" {
+		if path := profilePath(r.docPath, r.profile); fileExists(path) {
+			r.log(2, "Reading configuration document %s ...", path)
+
+			if profileDoc, err = readDocument(path); err != nil {
+				return err
+			}
+		}
+	}
+
+	r.doc, r.profileDoc = doc, profileDoc
+
+	return nil
+}
+
 // getFieldValue reads and returns the string value for a field from either
 //   - command-line flags,
 //   - environment variables,
-//   - or configuration files
+//   - configuration files,
+//   - additional sources (see WithSource option),
+//   - the configuration document,
+//   - or the default tag
 //
-// If the value is read from a file, the second returned value will be the file path.
-func (r *reader) getFieldValue(fieldName, flagName, envName, fileEnvName string) (string, string) {
-	var value, filePath string
-
-	// First, try reading from flag
-	if value == "" && flagName != skip && !r.skipFlag {
-		value = getFlagValue(flagName)
-		r.log(5, "[%s] value read from flag %s: %s", fieldName, flagName, value)
-	}
-
-	// Second, try reading from environment variable
-	if value == "" && envName != skip && !r.skipEnv {
-		value = os.Getenv(envName)
-		r.log(5, "[%s] value read from environment variable %s: %s", fieldName, envName, value)
-	}
-
-	// Third, try reading from file
-	if value == "" && fileEnvName != skip && !r.skipFileEnv {
-		// Read file environment variable
-		filePath = os.Getenv(fileEnvName)
-		r.log(5, "[%s] value read from file environment variable %s: %s", fieldName, fileEnvName, filePath)
-
-		if filePath != "" {
-			// Check for Telepresence
-			// See https://telepresence.io/howto/volumes.html for details
-			if r.telepresence {
-				if mountPath := os.Getenv(envTelepresenceRoot); mountPath != "" {
-					filePath = filepath.Join(mountPath, filePath)
-					r.log(5, "[%s] telepresence mount path: %s", fieldName, mountPath)
+// The second returned value is the source from which the value is read.
+// If the value is read from a file, the third returned value will be the file path.
+func (r *reader) getFieldValue(f fieldInfo) (string, string, string) {
+	var value, source, filePath string
+
+	key := f.getKey()
+
+	// Aliases (old names) have lower priority than the canonical name in all sources and documents
+	keys := append([]Key{key}, f.getAliasKeys(key)...)
+	var used Key
+
+	for _, k := range keys {
+		if value != "" {
+			break
+		}
+
+		// First, try reading from sources in order
+		for _, src := range r.getSources() {
+			if val, ok := src.Lookup(k); ok {
+				value, source, used = val, src.Name(), k
+				if fs, ok := src.(*fileSource); ok {
+					filePath = fs.path(k)
 				}
+				break
+			}
+		}
+
+		// Next, try reading from the configuration documents (the profile-specific document comes first)
+		for _, doc := range []*document{r.profileDoc, r.doc} {
+			if value != "" || len(k.Path) == 0 || doc == nil {
+				continue
 			}
 
-			// Read config file
-			filePath = filepath.Clean(filePath)
-			if b, err := os.ReadFile(filePath); err == nil {
-				value = string(b)
-				r.log(5, "[%s] value read from %s: %s", fieldName, filePath, value)
+			if val, ok := doc.lookup(k.Path, k.listSep, k.layout); ok {
+				value, source, used = val, sourceDocument, k
+				r.log(5, "[%s] value read from document key %s: %s", f.name, strings.Join(k.Path, "."), maskValue(f.secret, value))
 			}
 		}
 	}
 
-	return value, filePath
+	if value != "" {
+		r.warnDeprecated(f, source, key, used)
+	}
+
+	// Finally, fall back to the default value specified by the default tag
+	if value == "" && f.defaultVal != "" {
+		value, source = f.defaultVal, sourceDefault
+		r.log(5, "[%s] value read from default tag: %s", f.name, maskValue(f.secret, value))
+	}
+
+	value = r.expandValue(f, value)
+	if value == "" {
+		source = ""
+	}
+
+	r.protectValue(f, value)
+
+	return value, source, filePath
 }
 
-// notifySubscribers sends an update to every subscriber channel in a new go routine.
+// notifySubscribers queues an update for every subscriber.
+// Updates are delivered to subscriber channels in order by the watcher.
 func (r *reader) notifySubscribers(name string, value interface{}) {
 	if len(r.subscribers) == 0 {
 		return
@@ -202,57 +434,208 @@ func (r *reader) notifySubscribers(name string, value interface{}) {
 
 	r.log(4, "[%s] notifying %d subscribers ...", name, len(r.subscribers))
 
+	// Values of fields holding secrets are masked
+	if r.secrets.isField(name) {
+		value = mask
+	}
+
 	update := Update{
 		Name:  name,
 		Value: value,
 	}
 
-	for i, sub := range r.subscribers {
-		go func(id int, ch chan Update) {
-			r.log(4, "[%s] notifying subscriber %d ...", name, id)
-			ch <- update
-			r.log(4, "[%s] subscriber %d notified", name, id)
-		}(i, sub)
+	// Updates are held until new values are accepted (see WithValidator option)
+	if r.holding {
+		r.held = append(r.held, update)
+		return
+	}
+
+	for _, sub := range r.subscribers {
+		sub.push(update)
 	}
 }
 
-func (r *reader) iterateOnFields(vStruct reflect.Value, handle func(v reflect.Value, fieldName, flagName, envName, fileEnvName, listSep string)) {
+// hold starts holding updates instead of queueing them for subscribers.
+func (r *reader) hold() {
+	r.holding = true
+}
+
+// release stops holding updates and either queues the held updates for subscribers or drops them.
+func (r *reader) release(send bool) {
+	held := r.held
+	r.holding, r.held = false, nil
+
+	if !send {
+		return
+	}
+
+	for _, update := range held {
+		for _, sub := range r.subscribers {
+			sub.push(update)
+		}
+	}
+}
+
+// getFieldInfo determines the names of a struct field for every source.
+// The names of the parent field are used as prefixes (nested structs).
+// If the field is a struct itself, the returned names will be used as prefixes for its nested fields.
+func (r *reader) getFieldInfo(v reflect.Value, f reflect.StructField, parent fieldInfo, nested bool) fieldInfo {
+	name := f.Name
+	if parent.name != "" {
+		name = parent.name + "." + f.Name
+	}
+
+	// `flag:"..."`
+	flagName := f.Tag.Get(tagFlag)
+	if flagName == "" {
+		switch parent.flagName {
+		case "":
+			flagName = r.prefixFlag + getFlagName(f.Name)
+		case skip:
+			flagName = skip
+		default:
+			flagName = parent.flagName + "." + getFlagName(f.Name)
+		}
+	}
+
+	// `env:"..."`
+	envName := f.Tag.Get(tagEnv)
+	if envName == "" {
+		switch parent.envName {
+		case "":
+			envName = r.prefixEnv + getEnvVarName(f.Name)
+		case skip:
+			envName = skip
+		default:
+			envName = parent.envName + "_" + getEnvVarName(f.Name)
+		}
+	}
+
+	// `fileenv:"..."`
+	fileEnvName := f.Tag.Get(tagFileEnv)
+	if fileEnvName == "" {
+		fileEnvName = getFileEnvVarName(f.Name)
+		if nested {
+			// The prefix for nested fields should not have the _FILE suffix
+			fileEnvName = getEnvVarName(f.Name)
+		}
+
+		switch parent.fileEnvName {
+		case "":
+			fileEnvName = r.prefixFileEnv + fileEnvName
+		case skip:
+			fileEnvName = skip
+		default:
+			fileEnvName = parent.fileEnvName + "_" + fileEnvName
+		}
+	}
+
+	// `yaml:"..."`, `json:"..."`, or `toml:"..."`
+	var docFormat string
+	if r.doc != nil {
+		docFormat = r.doc.format
+	}
+
+	var docKeys []string
+	if key := getDocKey(f, docFormat); key == skip {
+		docKeys = []string{skip}
+	} else if len(parent.docKeys) > 0 && parent.docKeys[0] == skip {
+		docKeys = parent.docKeys
+	} else {
+		docKeys = append(append([]string{}, parent.docKeys...), key)
+	}
+
+	// `sep:"..."`
+	listSep := f.Tag.Get(tagSep)
+	if listSep == "" {
+		listSep = r.listSep
+	}
+
+	// `layout:"..."`
+	layout := f.Tag.Get(tagLayout)
+	if layout == "" {
+		layout = time.RFC3339
+	}
+
+	return fieldInfo{
+		value:       v,
+		name:        name,
+		flagName:    flagName,
+		envName:     envName,
+		fileEnvName: fileEnvName,
+		docKeys:     docKeys,
+		listSep:     listSep,
+		layout:      layout,
+		defaultVal:  f.Tag.Get(tagDefault),
+		validation:  getValidation(f),
+		secret:      isSecret(f),
+		desc:        f.Tag.Get(tagDesc),
+		aliases:     getAliases(f),
+		deprecated:  f.Tag.Get(tagDeprecated),
+	}
+}
+
+// iterateOnFields calls handle for every exported field of a struct with a supported type.
+// Nested structs and pointers to structs are iterated recursively.
+// Nil pointers to structs are initialized with new values.
+func (r *reader) iterateOnFields(vStruct reflect.Value, handle func(f fieldInfo)) {
+	r.iterateOnNestedFields(vStruct, fieldInfo{}, []reflect.Type{vStruct.Type()}, handle)
+}
+
+// iterateOnCopy calls handle for every field of a copy of a struct, so the struct is left untouched (i.e. nil pointers or new elements).
+func (r *reader) iterateOnCopy(vStruct reflect.Value, handle func(f fieldInfo)) {
+	r.iterateOnFields(cloneStruct(vStruct), handle)
+}
+
+func (r *reader) iterateOnNestedFields(vStruct reflect.Value, parent fieldInfo, visited []reflect.Type, handle func(f fieldInfo)) {
 	// Iterate over struct fields
 	for i := 0; i < vStruct.NumField(); i++ {
 		v := vStruct.Field(i)        // reflect.Value       --> vField.Kind(), vField.Type().Name(), vField.Type().Kind(), vField.Interface()
 		t := v.Type()                // reflect.Type        --> t.Kind(), t.PkgPath(), t.Name(), t.NumField()
 		f := vStruct.Type().Field(i) // reflect.StructField --> f.Name, f.Type.Name(), f.Type.Kind(), f.Tag.Get(tag)
 
-		// Skip unexported and unsupported fields
-		if !v.CanSet() || !isTypeSupported(t) {
+		// Skip unexported fields
+		if !v.CanSet() {
 			continue
 		}
 
-		// `flag:"..."`
-		flagName := f.Tag.Get(tagFlag)
-		if flagName == "" {
-			flagName = r.prefixFlag + getFlagName(f.Name)
+		if isTypeSupported(t) {
+			handle(r.getFieldInfo(v, f, parent, false))
+			continue
 		}
 
-		// `env:"..."`
-		envName := f.Tag.Get(tagEnv)
-		if envName == "" {
-			envName = r.prefixEnv + getEnvVarName(f.Name)
+		// Slices and maps of nested structs
+		if tElem, ok := getStructElem(t); ok {
+			if !slices.Contains(visited, tElem) {
+				r.iterateOnElems(v, f, parent, visited, handle)
+			}
+			continue
+		}
+
+		// Skip unsupported fields that are not nested structs
+		tStruct := t
+		if t.Kind() == reflect.Ptr {
+			tStruct = t.Elem()
+		}
+
+		if tStruct.Kind() != reflect.Struct || slices.Contains(visited, tStruct) {
+			continue
 		}
 
-		// `fileenv:"..."`
-		fileEnvName := f.Tag.Get(tagFileEnv)
-		if fileEnvName == "" {
-			fileEnvName = r.prefixFileEnv + getFileEnvVarName(f.Name)
+		if t.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				v.Set(reflect.New(tStruct))
+			}
+			v = v.Elem()
 		}
 
-		// `sep:"..."`
-		listSep := f.Tag.Get(tagSep)
-		if listSep == "" {
-			listSep = r.listSep
+		// Embedded structs without any name customization are flattened
+		nested := parent
+		if !f.Anonymous || f.Tag.Get(tagFlag) != "" || f.Tag.Get(tagEnv) != "" || f.Tag.Get(tagFileEnv) != "" {
+			nested = r.getFieldInfo(v, f, parent, true)
 		}
 
-		handle(v, f.Name, flagName, envName, fileEnvName, listSep)
+		r.iterateOnNestedFields(v, nested, append(visited, tStruct), handle)
 	}
 }
 
@@ -260,75 +643,212 @@ func (r *reader) registerFlags(vStruct reflect.Value) {
 	r.log(2, "Registering configuration flags ...")
 	r.log(2, line)
 
-	r.iterateOnFields(vStruct, func(v reflect.Value, fieldName, flagName, envName, fileEnvName, listSep string) {
-		if flagName == skip {
+	r.iterateOnFields(vStruct, func(f fieldInfo) {
+		if f.flagName == skip {
 			return
 		}
 
-		var dataType string
-		if v.Kind() == reflect.Slice {
-			dataType = fmt.Sprintf("[]%s", reflect.TypeOf(v.Interface()).Elem())
-		} else {
-			dataType = v.Type().String()
-		}
-
-		defaultValue := fmt.Sprintf("%v", v.Interface())
-
 		usage := fmt.Sprintf(
 			"%s:\t\t\t\t%s\n%s:\t\t\t\t%s\n%s:\t\t\t%s\n%s:\t%s",
-			"data type", dataType,
-			"default value", defaultValue,
-			"environment variable", envName,
-			"environment variable for file path", fileEnvName,
+			"data type", f.value.Type().String(),
+			"default value", maskValue(f.secret, getDefaultValue(f)),
+			"environment variable", f.envName,
+			"environment variable for file path", f.fileEnvName,
 		)
 
-		// Define a flag for the field, so flag.Parse() can be called
-		if flag.Lookup(flagName) == nil {
-			switch v.Kind() {
-			case reflect.Bool:
-				flag.Bool(flagName, v.Bool(), usage)
-			default:
-				flag.Var(&flagValue{}, flagName, usage)
-			}
+		if f.desc != "" {
+			usage = f.desc + "\n" + usage
+		}
+
+		if f.deprecated != "" {
+			usage = "Deprecated: " + f.deprecated + "\n" + usage
 		}
 
-		r.log(5, "[%s] flag registered: %s", fieldName, flagName)
+		r.registerFlag(f, f.flagName, usage)
+
+		// Aliases are registered too, so they can still be passed on the command line
+		for _, alias := range f.aliases {
+			r.registerFlag(f, getAliasFlagName(alias), fmt.Sprintf("Deprecated: use -%s instead", f.flagName))
+		}
 	})
 
+	r.registerHelp(vStruct)
+	r.registerPrintConfig()
+
 	r.log(5, line)
 }
 
-func (r *reader) readFields(vStruct reflect.Value) {
+// registerFlag defines a flag for a field either on the flag set or on the default flag set.
+func (r *reader) registerFlag(f fieldInfo, name, usage string) {
+	// Define a typed flag for the field on the flag set
+	if r.flagSet != nil {
+		if r.flagSet.Lookup(name) == nil {
+			r.flagSet.Var(&fieldFlag{r: r, f: f}, name, usage)
+		}
+		r.log(5, "[%s] flag registered on flag set: %s", f.name, name)
+		return
+	}
+
+	// Define a flag for the field, so flag.Parse() can be called
+	if flag.Lookup(name) == nil {
+		switch f.value.Kind() {
+		case reflect.Bool:
+			b := f.value.Bool()
+			if f.defaultVal != "" {
+				b, _ = strconv.ParseBool(f.defaultVal)
+			}
+			flag.Bool(name, b, usage)
+		default:
+			flag.Var(&flagValue{}, name, usage)
+		}
+	}
+
+	r.log(5, "[%s] flag registered: %s", f.name, name)
+}
+
+// parseFlags parses the command-line arguments using the flag set specified by the WithFlagSet option.
+// The values of flags are validated against the types of their fields at parse time.
+// If the flag set is already parsed, the values of the flags already set are used.
+func (r *reader) parseFlags() error {
+	if r.flagSet == nil {
+		return nil
+	}
+
+	if !r.flagSet.Parsed() {
+		r.log(2, "Parsing command-line flags ...")
+		if err := r.flagSet.Parse(r.flagArgs); err != nil {
+			return err
+		}
+	}
+
+	r.flagValues = map[string]string{}
+	r.flagSet.Visit(func(fl *flag.Flag) {
+		r.flagValues[fl.Name] = fl.Value.String()
+	})
+
+	return nil
+}
+
+// readFields reads and sets the values for all fields of a struct.
+// It returns an error listing all values that cannot be set on their fields unless the lenient option is set.
+func (r *reader) readFields(vStruct reflect.Value) error {
+	var errs error
+
 	r.log(2, "Reading configuration values ...")
 	r.log(2, line)
 
-	r.iterateOnFields(vStruct, func(v reflect.Value, fieldName, flagName, envName, fileEnvName, listSep string) {
-		r.log(5, "[%s] expecting flag name: %s", fieldName, flagName)
-		r.log(5, "[%s] expecting environment variable name: %s", fieldName, envName)
-		r.log(5, "[%s] expecting file environment variable name: %s", fieldName, fileEnvName)
-		r.log(5, "[%s] expecting list separator: %s", fieldName, listSep)
+	r.iterateOnFields(vStruct, func(f fieldInfo) {
+		r.log(5, "[%s] expecting flag name: %s", f.name, f.flagName)
+		r.log(5, "[%s] expecting environment variable name: %s", f.name, f.envName)
+		r.log(5, "[%s] expecting file environment variable name: %s", f.name, f.fileEnvName)
+		r.log(5, "[%s] expecting document key: %s", f.name, strings.Join(f.docKeys, "."))
+		r.log(5, "[%s] expecting list separator: %s", f.name, f.listSep)
+		r.log(5, "[%s] expecting time layout: %s", f.name, f.layout)
+		r.log(5, "[%s] expecting default value: %s", f.name, maskValue(f.secret, f.defaultVal))
 		defer r.log(5, line)
 
+		// Keep the track of all fields, so they can be read again when sources are changed
+		r.fields = append(r.fields, f)
+
 		// Try reading the configuration value for current field
-		val, path := r.getFieldValue(fieldName, flagName, envName, fileEnvName)
+		val, source, path := r.getFieldValue(f)
 
 		// If no value, skip this field
 		if val == "" {
-			r.log(5, "[%s] falling back to default value: %v", fieldName, v.Interface())
+			r.log(5, "[%s] falling back to default value: %s", f.name, maskValue(f.secret, fmt.Sprintf("%v", f.value.Interface())))
+			r.reportField(f, "", "", "")
 			return
 		}
 
-		f := fieldInfo{
-			value:   v,
-			name:    fieldName,
-			listSep: listSep,
-		}
-
 		// Keep the track of which fields are read from which files
 		if path != "" {
 			r.filesToFields[path] = f
 		}
 
-		_, _ = r.setFieldValue(f, val)
+		// Resolve the value if it references a secret
+		resolved, err := r.resolveValue(f, val)
+		if err == nil {
+			if _, err = r.setFieldValue(f, resolved); err != nil && (f.secret || resolved != val) {
+				// Errors may include the secret value
+				err = maskError(err, val, resolved)
+			}
+		}
+
+		if err != nil {
+			r.log(1, "[%s] cannot set value from %s: %s", f.name, source, err)
+			if !r.lenient {
+				errs = multierror.Append(errs, &FieldError{
+					Field:  f.name,
+					Source: source,
+					Value:  maskValue(f.secret, val),
+					Err:    err,
+				})
+			}
+
+			// The field keeps its default value and is not validated since the value is already reported
+			if r.failed == nil {
+				r.failed = map[string]bool{}
+			}
+			r.failed[f.name] = true
+
+			r.reportField(f, "", "", "")
+			return
+		}
+
+		r.reportField(f, source, path, val)
+	})
+
+	return errs
+}
+
+// validateFields validates the values of all fields of a struct against their validation tags.
+// It returns an error listing all fields that fail validation.
+// Fields whose values cannot be set are skipped.
+func (r *reader) validateFields(vStruct reflect.Value) error {
+	var errs error
+
+	r.log(2, "Validating configuration values ...")
+	r.log(2, line)
+
+	r.iterateOnFields(vStruct, func(f fieldInfo) {
+		if r.failed[f.name] {
+			return
+		}
+
+		if err := validateField(f); err != nil {
+			r.log(1, "[%s] %s", f.name, err)
+			errs = multierror.Append(errs, err)
+		}
 	})
+
+	return errs
+}
+
+// tryFieldValue sets a value on a temporary copy of a field without notifying subscribers.
+// It returns the temporary copy of the field.
+func (r *reader) tryFieldValue(f fieldInfo, val string) (fieldInfo, error) {
+	quiet := *r
+	quiet.subscribers = nil
+
+	candidate := f
+	candidate.value = reflect.New(f.value.Type()).Elem()
+	candidate.store = nil
+
+	_, err := quiet.setFieldValue(candidate, val)
+	return candidate, err
+}
+
+// updateField sets a new value on a field only if the new value is valid.
+// The new value is first set and validated on a temporary copy of the field.
+func (r *reader) updateField(f fieldInfo, val string) (bool, error) {
+	candidate, err := r.tryFieldValue(f, val)
+	if err != nil {
+		return false, err
+	}
+
+	if err := validateField(candidate); err != nil {
+		return false, err
+	}
+
+	return r.setFieldValue(f, val)
 }
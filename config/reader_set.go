@@ -15,7 +15,7 @@ func (r *reader) setString(v reflect.Value, name, val string) (bool, error) {
 		return false, nil
 	}
 
-	r.log(5, "[%s] setting string value: %s", name, val)
+	r.log(5, "[%s] setting string value: %s", name, r.logValue(name, val))
 	v.SetString(val)
 	r.notifySubscribers(name, val)
 
@@ -32,7 +32,7 @@ func (r *reader) setBool(v reflect.Value, name, val string) (bool, error) {
 		return false, nil
 	}
 
-	r.log(5, "[%s] setting bool value: %t", name, b)
+	r.log(5, "[%s] setting bool value: %t", name, r.logValue(name, b))
 	v.SetBool(b)
 	r.notifySubscribers(name, b)
 
@@ -49,7 +49,7 @@ func (r *reader) setFloat32(v reflect.Value, name, val string) (bool, error) {
 		return false, nil
 	}
 
-	r.log(5, "[%s] setting float32 value: %f", name, f)
+	r.log(5, "[%s] setting float32 value: %f", name, r.logValue(name, f))
 	v.SetFloat(f)
 	r.notifySubscribers(name, float32(f))
 
@@ -66,7 +66,7 @@ func (r *reader) setFloat64(v reflect.Value, name, val string) (bool, error) {
 		return false, nil
 	}
 
-	r.log(5, "[%s] setting float64 value: %f", name, f)
+	r.log(5, "[%s] setting float64 value: %f", name, r.logValue(name, f))
 	v.SetFloat(f)
 	r.notifySubscribers(name, f)
 
@@ -84,7 +84,7 @@ func (r *reader) setInt(v reflect.Value, name, val string) (bool, error) {
 		return false, nil
 	}
 
-	r.log(5, "[%s] setting int value: %d", name, i)
+	r.log(5, "[%s] setting int value: %d", name, r.logValue(name, i))
 	v.SetInt(i)
 	r.notifySubscribers(name, int(i))
 
@@ -101,7 +101,7 @@ func (r *reader) setInt8(v reflect.Value, name, val string) (bool, error) {
 		return false, nil
 	}
 
-	r.log(5, "[%s] setting int8 value: %d", name, i)
+	r.log(5, "[%s] setting int8 value: %d", name, r.logValue(name, i))
 	v.SetInt(i)
 	r.notifySubscribers(name, int8(i))
 
@@ -118,7 +118,7 @@ func (r *reader) setInt16(v reflect.Value, name, val string) (bool, error) {
 		return false, nil
 	}
 
-	r.log(5, "[%s] setting int16 value: %d", name, i)
+	r.log(5, "[%s] setting int16 value: %d", name, r.logValue(name, i))
 	v.SetInt(i)
 	r.notifySubscribers(name, int16(i))
 
@@ -135,7 +135,7 @@ func (r *reader) setInt32(v reflect.Value, name, val string) (bool, error) {
 		return false, nil
 	}
 
-	r.log(5, "[%s] setting int32 value: %d", name, i)
+	r.log(5, "[%s] setting int32 value: %d", name, r.logValue(name, i))
 	v.SetInt(i)
 	r.notifySubscribers(name, int32(i))
 
@@ -153,7 +153,7 @@ func (r *reader) setInt64(v reflect.Value, name, val string) (bool, error) {
 			return false, nil
 		}
 
-		r.log(5, "[%s] setting duration value: %s", name, d)
+		r.log(5, "[%s] setting duration value: %s", name, r.logValue(name, d))
 		v.Set(reflect.ValueOf(d))
 		r.notifySubscribers(name, d)
 
@@ -169,7 +169,7 @@ func (r *reader) setInt64(v reflect.Value, name, val string) (bool, error) {
 		return false, nil
 	}
 
-	r.log(5, "[%s] setting int64 value: %d", name, i)
+	r.log(5, "[%s] setting int64 value: %d", name, r.logValue(name, i))
 	v.SetInt(i)
 	r.notifySubscribers(name, i)
 
@@ -187,7 +187,7 @@ func (r *reader) setUint(v reflect.Value, name, val string) (bool, error) {
 		return false, nil
 	}
 
-	r.log(5, "[%s] setting uint value: %d", name, u)
+	r.log(5, "[%s] setting uint value: %d", name, r.logValue(name, u))
 	v.SetUint(u)
 	r.notifySubscribers(name, uint(u))
 
@@ -204,7 +204,7 @@ func (r *reader) setUint8(v reflect.Value, name, val string) (bool, error) {
 		return false, nil
 	}
 
-	r.log(5, "[%s] setting uint8 value: %d", name, u)
+	r.log(5, "[%s] setting uint8 value: %d", name, r.logValue(name, u))
 	v.SetUint(u)
 	r.notifySubscribers(name, uint8(u))
 
@@ -221,7 +221,7 @@ func (r *reader) setUint16(v reflect.Value, name, val string) (bool, error) {
 		return false, nil
 	}
 
-	r.log(5, "[%s] setting uint16 value: %d", name, u)
+	r.log(5, "[%s] setting uint16 value: %d", name, r.logValue(name, u))
 	v.SetUint(u)
 	r.notifySubscribers(name, uint16(u))
 
@@ -238,7 +238,7 @@ func (r *reader) setUint32(v reflect.Value, name, val string) (bool, error) {
 		return false, nil
 	}
 
-	r.log(5, "[%s] setting uint32 value: %d", name, u)
+	r.log(5, "[%s] setting uint32 value: %d", name, r.logValue(name, u))
 	v.SetUint(u)
 	r.notifySubscribers(name, uint32(u))
 
@@ -255,7 +255,7 @@ func (r *reader) setUint64(v reflect.Value, name, val string) (bool, error) {
 		return false, nil
 	}
 
-	r.log(5, "[%s] setting unsigned integer value: %d", name, u)
+	r.log(5, "[%s] setting unsigned integer value: %d", name, r.logValue(name, u))
 	v.SetUint(u)
 	r.notifySubscribers(name, u)
 
@@ -277,7 +277,7 @@ func (r *reader) setStruct(v reflect.Value, name, val string) (bool, error) {
 		}
 
 		// u is a pointer
-		r.log(5, "[%s] setting url value: %s", name, val)
+		r.log(5, "[%s] setting url value: %s", name, r.logValue(name, val))
 		v.Set(reflect.ValueOf(u).Elem())
 		r.notifySubscribers(name, *u)
 
@@ -294,7 +294,7 @@ func (r *reader) setStruct(v reflect.Value, name, val string) (bool, error) {
 		}
 
 		// r is a pointer
-		r.log(5, "[%s] setting regexp value: %s", name, val)
+		r.log(5, "[%s] setting regexp value: %s", name, r.logValue(name, val))
 		v.Set(reflect.ValueOf(re).Elem())
 		r.notifySubscribers(name, *re)
 
@@ -304,12 +304,61 @@ func (r *reader) setStruct(v reflect.Value, name, val string) (bool, error) {
 	return false, fmt.Errorf("unsupported type: %s.%s", t.PkgPath(), t.Name())
 }
 
+func (r *reader) setTime(v reflect.Value, name, layout, val string) (bool, error) {
+	t, err := time.Parse(layout, val)
+	if err != nil {
+		return false, err
+	}
+
+	if v.Interface().(time.Time).Equal(t) {
+		return false, nil
+	}
+
+	r.log(5, "[%s] setting time value: %s", name, r.logValue(name, t))
+	v.Set(reflect.ValueOf(t))
+	r.notifySubscribers(name, t)
+
+	return true, nil
+}
+
+// makeStringMap creates a map of the given type (i.e. map[string]string or map[Key]string) from key=value entries.
+func makeStringMap(t reflect.Type, vals []string) (reflect.Value, error) {
+	m := reflect.MakeMapWithSize(t, len(vals))
+	for _, val := range vals {
+		key, value, ok := strings.Cut(val, "=")
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("invalid map entry: %s", val)
+		}
+
+		m.SetMapIndex(reflect.ValueOf(key).Convert(t.Key()), reflect.ValueOf(value).Convert(t.Elem()))
+	}
+
+	return m, nil
+}
+
+func (r *reader) setStringMap(v reflect.Value, name string, vals []string) (bool, error) {
+	m, err := makeStringMap(v.Type(), vals)
+	if err != nil {
+		return false, err
+	}
+
+	if reflect.DeepEqual(v.Interface(), m.Interface()) {
+		return false, nil
+	}
+
+	r.log(5, "[%s] setting string map: %v", name, r.logValue(name, m))
+	v.Set(m)
+	r.notifySubscribers(name, m.Interface())
+
+	return true, nil
+}
+
 func (r *reader) setStringPtr(v reflect.Value, name, val string) (bool, error) {
 	if !v.IsZero() && v.Elem().String() == val {
 		return false, nil
 	}
 
-	r.log(5, "[%s] setting string pointer: %s", name, val)
+	r.log(5, "[%s] setting string pointer: %s", name, r.logValue(name, val))
 	v.Set(reflect.ValueOf(&val))
 	r.notifySubscribers(name, &val)
 
@@ -326,7 +375,7 @@ func (r *reader) setBoolPtr(v reflect.Value, name, val string) (bool, error) {
 		return false, nil
 	}
 
-	r.log(5, "[%s] setting bool pointer: %t", name, b)
+	r.log(5, "[%s] setting bool pointer: %t", name, r.logValue(name, b))
 	v.Set(reflect.ValueOf(&b))
 	r.notifySubscribers(name, &b)
 
@@ -344,7 +393,7 @@ func (r *reader) setFloat32Ptr(v reflect.Value, name, val string) (bool, error)
 	}
 
 	f32 := float32(f64)
-	r.log(5, "[%s] setting float32 pointer: %f", name, f32)
+	r.log(5, "[%s] setting float32 pointer: %f", name, r.logValue(name, f32))
 	v.Set(reflect.ValueOf(&f32))
 	r.notifySubscribers(name, &f32)
 
@@ -361,7 +410,7 @@ func (r *reader) setFloat64Ptr(v reflect.Value, name, val string) (bool, error)
 		return false, nil
 	}
 
-	r.log(5, "[%s] setting float64 pointer: %f", name, f64)
+	r.log(5, "[%s] setting float64 pointer: %f", name, r.logValue(name, f64))
 	v.Set(reflect.ValueOf(&f64))
 	r.notifySubscribers(name, &f64)
 
@@ -380,7 +429,7 @@ func (r *reader) setIntPtr(v reflect.Value, name, val string) (bool, error) {
 	}
 
 	i := int(i64)
-	r.log(5, "[%s] setting int pointer: %d", name, i)
+	r.log(5, "[%s] setting int pointer: %d", name, r.logValue(name, i))
 	v.Set(reflect.ValueOf(&i))
 	r.notifySubscribers(name, &i)
 
@@ -398,7 +447,7 @@ func (r *reader) setInt8Ptr(v reflect.Value, name, val string) (bool, error) {
 	}
 
 	i8 := int8(i64)
-	r.log(5, "[%s] setting int8 pointer: %d", name, i8)
+	r.log(5, "[%s] setting int8 pointer: %d", name, r.logValue(name, i8))
 	v.Set(reflect.ValueOf(&i8))
 	r.notifySubscribers(name, &i8)
 
@@ -416,7 +465,7 @@ func (r *reader) setInt16Ptr(v reflect.Value, name, val string) (bool, error) {
 	}
 
 	i16 := int16(i64)
-	r.log(5, "[%s] setting int16 pointer: %d", name, i16)
+	r.log(5, "[%s] setting int16 pointer: %d", name, r.logValue(name, i16))
 	v.Set(reflect.ValueOf(&i16))
 	r.notifySubscribers(name, &i16)
 
@@ -434,7 +483,7 @@ func (r *reader) setInt32Ptr(v reflect.Value, name, val string) (bool, error) {
 	}
 
 	i32 := int32(i64)
-	r.log(5, "[%s] setting int32 pointer: %d", name, i32)
+	r.log(5, "[%s] setting int32 pointer: %d", name, r.logValue(name, i32))
 	v.Set(reflect.ValueOf(&i32))
 	r.notifySubscribers(name, &i32)
 
@@ -454,7 +503,7 @@ func (r *reader) setInt64Ptr(v reflect.Value, name, val string) (bool, error) {
 			return false, nil
 		}
 
-		r.log(5, "[%s] setting duration pointer: %s", name, d)
+		r.log(5, "[%s] setting duration pointer: %s", name, r.logValue(name, d))
 		v.Set(reflect.ValueOf(&d))
 		r.notifySubscribers(name, &d)
 
@@ -470,7 +519,7 @@ func (r *reader) setInt64Ptr(v reflect.Value, name, val string) (bool, error) {
 		return false, nil
 	}
 
-	r.log(5, "[%s] setting int64 pointer: %d", name, i64)
+	r.log(5, "[%s] setting int64 pointer: %d", name, r.logValue(name, i64))
 	v.Set(reflect.ValueOf(&i64))
 	r.notifySubscribers(name, &i64)
 
@@ -489,7 +538,7 @@ func (r *reader) setUintPtr(v reflect.Value, name, val string) (bool, error) {
 	}
 
 	u := uint(u64)
-	r.log(5, "[%s] setting uint pointer: %d", name, u)
+	r.log(5, "[%s] setting uint pointer: %d", name, r.logValue(name, u))
 	v.Set(reflect.ValueOf(&u))
 	r.notifySubscribers(name, &u)
 
@@ -507,7 +556,7 @@ func (r *reader) setUint8Ptr(v reflect.Value, name, val string) (bool, error) {
 	}
 
 	u8 := uint8(u64)
-	r.log(5, "[%s] setting uint8 pointer: %d", name, u8)
+	r.log(5, "[%s] setting uint8 pointer: %d", name, r.logValue(name, u8))
 	v.Set(reflect.ValueOf(&u8))
 	r.notifySubscribers(name, &u8)
 
@@ -525,7 +574,7 @@ func (r *reader) setUint16Ptr(v reflect.Value, name, val string) (bool, error) {
 	}
 
 	u16 := uint16(u64)
-	r.log(5, "[%s] setting uint16 pointer: %d", name, u16)
+	r.log(5, "[%s] setting uint16 pointer: %d", name, r.logValue(name, u16))
 	v.Set(reflect.ValueOf(&u16))
 	r.notifySubscribers(name, &u16)
 
@@ -543,7 +592,7 @@ func (r *reader) setUint32Ptr(v reflect.Value, name, val string) (bool, error) {
 	}
 
 	u32 := uint32(u64)
-	r.log(5, "[%s] setting uint32 pointer: %d", name, u32)
+	r.log(5, "[%s] setting uint32 pointer: %d", name, r.logValue(name, u32))
 	v.Set(reflect.ValueOf(&u32))
 	r.notifySubscribers(name, &u32)
 
@@ -560,7 +609,7 @@ func (r *reader) setUint64Ptr(v reflect.Value, name, val string) (bool, error) {
 		return false, nil
 	}
 
-	r.log(5, "[%s] setting uint pointer: %d", name, u64)
+	r.log(5, "[%s] setting uint pointer: %d", name, r.logValue(name, u64))
 	v.Set(reflect.ValueOf(&u64))
 	r.notifySubscribers(name, &u64)
 
@@ -581,7 +630,7 @@ func (r *reader) setStructPtr(v reflect.Value, name, val string) (bool, error) {
 		}
 
 		// u is a pointer
-		r.log(5, "[%s] setting url pointer: %s", name, val)
+		r.log(5, "[%s] setting url pointer: %s", name, r.logValue(name, val))
 		v.Set(reflect.ValueOf(u))
 		r.notifySubscribers(name, u)
 
@@ -597,7 +646,7 @@ func (r *reader) setStructPtr(v reflect.Value, name, val string) (bool, error) {
 		}
 
 		// r is a pointer
-		r.log(5, "[%s] setting regexp pointer: %s", name, val)
+		r.log(5, "[%s] setting regexp pointer: %s", name, r.logValue(name, val))
 		v.Set(reflect.ValueOf(re))
 		r.notifySubscribers(name, re)
 
@@ -607,12 +656,49 @@ func (r *reader) setStructPtr(v reflect.Value, name, val string) (bool, error) {
 	return false, fmt.Errorf("unsupported type: %s.%s", t.PkgPath(), t.Name())
 }
 
+func (r *reader) setTimePtr(v reflect.Value, name, layout, val string) (bool, error) {
+	t, err := time.Parse(layout, val)
+	if err != nil {
+		return false, err
+	}
+
+	if !v.IsZero() && v.Elem().Interface().(time.Time).Equal(t) {
+		return false, nil
+	}
+
+	r.log(5, "[%s] setting time pointer: %s", name, r.logValue(name, t))
+	v.Set(reflect.ValueOf(&t))
+	r.notifySubscribers(name, &t)
+
+	return true, nil
+}
+
+func (r *reader) setStringMapPtr(v reflect.Value, name string, vals []string) (bool, error) {
+	m, err := makeStringMap(v.Type().Elem(), vals)
+	if err != nil {
+		return false, err
+	}
+
+	if !v.IsZero() && reflect.DeepEqual(v.Elem().Interface(), m.Interface()) {
+		return false, nil
+	}
+
+	p := reflect.New(m.Type())
+	p.Elem().Set(m)
+
+	r.log(5, "[%s] setting string map pointer: %v", name, r.logValue(name, m))
+	v.Set(p)
+	r.notifySubscribers(name, p.Interface())
+
+	return true, nil
+}
+
 func (r *reader) setStringSlice(v reflect.Value, name string, vals []string) (bool, error) {
 	if reflect.DeepEqual(v.Interface(), vals) {
 		return false, nil
 	}
 
-	r.log(5, "[%s] setting string slice: %v", name, vals)
+	r.log(5, "[%s] setting string slice: %v", name, r.logValue(name, vals))
 	v.Set(reflect.ValueOf(vals))
 	r.notifySubscribers(name, vals)
 
@@ -634,7 +720,7 @@ func (r *reader) setBoolSlice(v reflect.Value, name string, vals []string) (bool
 		return false, nil
 	}
 
-	r.log(5, "[%s] setting bool slice: %v", name, bools)
+	r.log(5, "[%s] setting bool slice: %v", name, r.logValue(name, bools))
 	v.Set(reflect.ValueOf(bools))
 	r.notifySubscribers(name, bools)
 
@@ -656,7 +742,7 @@ func (r *reader) setFloat32Slice(v reflect.Value, name string, vals []string) (b
 		return false, nil
 	}
 
-	r.log(5, "[%s] setting float32 slice: %v", name, floats)
+	r.log(5, "[%s] setting float32 slice: %v", name, r.logValue(name, floats))
 	v.Set(reflect.ValueOf(floats))
 	r.notifySubscribers(name, floats)
 
@@ -678,7 +764,7 @@ func (r *reader) setFloat64Slice(v reflect.Value, name string, vals []string) (b
 		return false, nil
 	}
 
-	r.log(5, "[%s] setting float64 slice: %v", name, floats)
+	r.log(5, "[%s] setting float64 slice: %v", name, r.logValue(name, floats))
 	v.Set(reflect.ValueOf(floats))
 	r.notifySubscribers(name, floats)
 
@@ -701,7 +787,7 @@ func (r *reader) setIntSlice(v reflect.Value, name string, vals []string) (bool,
 		return false, nil
 	}
 
-	r.log(5, "[%s] setting int slice: %v", name, ints)
+	r.log(5, "[%s] setting int slice: %v", name, r.logValue(name, ints))
 	v.Set(reflect.ValueOf(ints))
 	r.notifySubscribers(name, ints)
 
@@ -723,7 +809,7 @@ func (r *reader) setInt8Slice(v reflect.Value, name string, vals []string) (bool
 		return false, nil
 	}
 
-	r.log(5, "[%s] setting int8 slice: %v", name, ints)
+	r.log(5, "[%s] setting int8 slice: %v", name, r.logValue(name, ints))
 	v.Set(reflect.ValueOf(ints))
 	r.notifySubscribers(name, ints)
 
@@ -745,7 +831,7 @@ func (r *reader) setInt16Slice(v reflect.Value, name string, vals []string) (boo
 		return false, nil
 	}
 
-	r.log(5, "[%s] setting int16 slice: %v", name, ints)
+	r.log(5, "[%s] setting int16 slice: %v", name, r.logValue(name, ints))
 	v.Set(reflect.ValueOf(ints))
 	r.notifySubscribers(name, ints)
 
@@ -767,7 +853,7 @@ func (r *reader) setInt32Slice(v reflect.Value, name string, vals []string) (boo
 		return false, nil
 	}
 
-	r.log(5, "[%s] setting int32 slice: %v", name, ints)
+	r.log(5, "[%s] setting int32 slice: %v", name, r.logValue(name, ints))
 	v.Set(reflect.ValueOf(ints))
 	r.notifySubscribers(name, ints)
 
@@ -792,7 +878,7 @@ func (r *reader) setInt64Slice(v reflect.Value, name string, vals []string) (boo
 			return false, nil
 		}
 
-		r.log(5, "[%s] setting duration slice: %v", name, durations)
+		r.log(5, "[%s] setting duration slice: %v", name, r.logValue(name, durations))
 		v.Set(reflect.ValueOf(durations))
 		r.notifySubscribers(name, durations)
 
@@ -813,7 +899,7 @@ func (r *reader) setInt64Slice(v reflect.Value, name string, vals []string) (boo
 		return false, nil
 	}
 
-	r.log(5, "[%s] setting int64 slice: %v", name, ints)
+	r.log(5, "[%s] setting int64 slice: %v", name, r.logValue(name, ints))
 	v.Set(reflect.ValueOf(ints))
 	r.notifySubscribers(name, ints)
 
@@ -836,7 +922,7 @@ func (r *reader) setUintSlice(v reflect.Value, name string, vals []string) (bool
 		return false, nil
 	}
 
-	r.log(5, "[%s] setting uint slice: %v", name, uints)
+	r.log(5, "[%s] setting uint slice: %v", name, r.logValue(name, uints))
 	v.Set(reflect.ValueOf(uints))
 	r.notifySubscribers(name, uints)
 
@@ -858,7 +944,7 @@ func (r *reader) setUint8Slice(v reflect.Value, name string, vals []string) (boo
 		return false, nil
 	}
 
-	r.log(5, "[%s] setting uint8 slice: %v", name, uints)
+	r.log(5, "[%s] setting uint8 slice: %v", name, r.logValue(name, uints))
 	v.Set(reflect.ValueOf(uints))
 	r.notifySubscribers(name, uints)
 
@@ -880,7 +966,7 @@ func (r *reader) setUint16Slice(v reflect.Value, name string, vals []string) (bo
 		return false, nil
 	}
 
-	r.log(5, "[%s] setting uint16 slice: %v", name, uints)
+	r.log(5, "[%s] setting uint16 slice: %v", name, r.logValue(name, uints))
 	v.Set(reflect.ValueOf(uints))
 	r.notifySubscribers(name, uints)
 
@@ -902,7 +988,7 @@ func (r *reader) setUint32Slice(v reflect.Value, name string, vals []string) (bo
 		return false, nil
 	}
 
-	r.log(5, "[%s] setting uint32 slice: %v", name, uints)
+	r.log(5, "[%s] setting uint32 slice: %v", name, r.logValue(name, uints))
 	v.Set(reflect.ValueOf(uints))
 	r.notifySubscribers(name, uints)
 
@@ -924,7 +1010,7 @@ func (r *reader) setUint64Slice(v reflect.Value, name string, vals []string) (bo
 		return false, nil
 	}
 
-	r.log(5, "[%s] setting uint64 slice: %v", name, uints)
+	r.log(5, "[%s] setting uint64 slice: %v", name, r.logValue(name, uints))
 	v.Set(reflect.ValueOf(uints))
 	r.notifySubscribers(name, uints)
 
@@ -950,7 +1036,7 @@ func (r *reader) setStructSlice(v reflect.Value, name string, vals []string) (bo
 			return false, nil
 		}
 
-		r.log(5, "[%s] setting url slice: %v", name, urls)
+		r.log(5, "[%s] setting url slice: %v", name, r.logValue(name, urls))
 		v.Set(reflect.ValueOf(urls))
 		r.notifySubscribers(name, urls)
 
@@ -971,7 +1057,7 @@ func (r *reader) setStructSlice(v reflect.Value, name string, vals []string) (bo
 			return false, nil
 		}
 
-		r.log(5, "[%s] setting regexp slice: %v", name, regexps)
+		r.log(5, "[%s] setting regexp slice: %v", name, r.logValue(name, regexps))
 		v.Set(reflect.ValueOf(regexps))
 		r.notifySubscribers(name, regexps)
 
@@ -981,7 +1067,104 @@ func (r *reader) setStructSlice(v reflect.Value, name string, vals []string) (bo
 	return false, fmt.Errorf("unsupported type: %s.%s", t.PkgPath(), t.Name())
 }
 
+func (r *reader) setTimeSlice(v reflect.Value, name, layout string, vals []string) (bool, error) {
+	times := []time.Time{}
+	for _, val := range vals {
+		t, err := time.Parse(layout, val)
+		if err != nil {
+			return false, err
+		}
+
+		times = append(times, t)
+	}
+
+	if reflect.DeepEqual(v.Interface(), times) {
+		return false, nil
+	}
+
+	r.log(5, "[%s] setting time slice: %v", name, r.logValue(name, times))
+	v.Set(reflect.ValueOf(times))
+	r.notifySubscribers(name, times)
+
+	return true, nil
+}
+
+func (r *reader) setCustom(v reflect.Value, name, val string) (bool, error) {
+	d, err := decodeValue(v.Type(), val)
+	if err != nil {
+		return false, err
+	}
+
+	if reflect.DeepEqual(v.Interface(), d.Interface()) {
+		return false, nil
+	}
+
+	r.log(5, "[%s] setting %s value: %v", name, v.Type(), r.logValue(name, d))
+	v.Set(d)
+	r.notifySubscribers(name, d.Interface())
+
+	return true, nil
+}
+
+func (r *reader) setCustomPtr(v reflect.Value, name, val string) (bool, error) {
+	d, err := decodeValue(v.Type().Elem(), val)
+	if err != nil {
+		return false, err
+	}
+
+	if !v.IsZero() && reflect.DeepEqual(v.Elem().Interface(), d.Interface()) {
+		return false, nil
+	}
+
+	p := reflect.New(d.Type())
+	p.Elem().Set(d)
+
+	r.log(5, "[%s] setting %s pointer: %v", name, d.Type(), r.logValue(name, d))
+	v.Set(p)
+	r.notifySubscribers(name, p.Interface())
+
+	return true, nil
+}
+
+func (r *reader) setCustomSlice(v reflect.Value, name string, vals []string) (bool, error) {
+	t := v.Type().Elem()
+	slice := reflect.MakeSlice(v.Type(), 0, len(vals))
+	for _, val := range vals {
+		d, err := decodeValue(t, val)
+		if err != nil {
+			return false, err
+		}
+
+		slice = reflect.Append(slice, d)
+	}
+
+	if reflect.DeepEqual(v.Interface(), slice.Interface()) {
+		return false, nil
+	}
+
+	r.log(5, "[%s] setting %s slice: %v", name, t, r.logValue(name, slice))
+	v.Set(slice)
+	r.notifySubscribers(name, slice.Interface())
+
+	return true, nil
+}
+
 func (r *reader) setFieldValue(f fieldInfo, val string) (bool, error) {
+	if f.store != nil {
+		defer f.store()
+	}
+
+	// Custom decoders take precedence over kinds
+	switch t := f.value.Type(); {
+	case hasDecoder(t):
+		return r.setCustom(f.value, f.name, val)
+	case t.Kind() == reflect.Ptr && hasDecoder(t.Elem()):
+		return r.setCustomPtr(f.value, f.name, val)
+	case t.Kind() == reflect.Slice && hasDecoder(t.Elem()):
+		vals := strings.Split(val, f.listSep)
+		return r.setCustomSlice(f.value, f.name, vals)
+	}
+
 	switch f.value.Kind() {
 	case reflect.String:
 		return r.setString(f.value, f.name, val)
@@ -1012,7 +1195,13 @@ func (r *reader) setFieldValue(f fieldInfo, val string) (bool, error) {
 	case reflect.Uint64:
 		return r.setUint64(f.value, f.name, val)
 	case reflect.Struct:
+		if t := f.value.Type(); t.PkgPath() == "time" && t.Name() == "Time" {
+			return r.setTime(f.value, f.name, f.layout, val)
+		}
 		return r.setStruct(f.value, f.name, val)
+	case reflect.Map:
+		vals := strings.Split(val, f.listSep)
+		return r.setStringMap(f.value, f.name, vals)
 
 	case reflect.Ptr:
 		tPtr := reflect.TypeOf(f.value.Interface()).Elem()
@@ -1047,7 +1236,13 @@ func (r *reader) setFieldValue(f fieldInfo, val string) (bool, error) {
 		case reflect.Uint64:
 			return r.setUint64Ptr(f.value, f.name, val)
 		case reflect.Struct:
+			if tPtr.PkgPath() == "time" && tPtr.Name() == "Time" {
+				return r.setTimePtr(f.value, f.name, f.layout, val)
+			}
 			return r.setStructPtr(f.value, f.name, val)
+		case reflect.Map:
+			vals := strings.Split(val, f.listSep)
+			return r.setStringMapPtr(f.value, f.name, vals)
 		}
 
 	case reflect.Slice:
@@ -1084,6 +1279,9 @@ func (r *reader) setFieldValue(f fieldInfo, val string) (bool, error) {
 		case reflect.Uint64:
 			return r.setUint64Slice(f.value, f.name, vals)
 		case reflect.Struct:
+			if tSlice.PkgPath() == "time" && tSlice.Name() == "Time" {
+				return r.setTimeSlice(f.value, f.name, f.layout, vals)
+			}
 			return r.setStructSlice(f.value, f.name, vals)
 		}
 	}
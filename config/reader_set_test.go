@@ -811,6 +811,160 @@ func TestReaderSetStructRegexp(t *testing.T) {
 	}
 }
 
+func TestReaderSetTime(t *testing.T) {
+	t1 := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	t2 := time.Date(2021, 1, 1, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name            string
+		t               time.Time
+		layout          string
+		val             string
+		expectedUpdated bool
+		expectedError   string
+		expectedResult  time.Time
+	}{
+		{
+			"NewValue",
+			t1, time.RFC3339, "2021-01-01T10:30:00Z",
+			true, "",
+			t2,
+		},
+		{
+			"NoNewValue",
+			t2, time.RFC3339, "2021-01-01T10:30:00Z",
+			false, "",
+			t2,
+		},
+		{
+			"CustomLayout",
+			t2, time.DateOnly, "2020-01-01",
+			true, "",
+			t1,
+		},
+		{
+			"InvalidValue",
+			t1, time.RFC3339, "2021-01-01",
+			false, `parsing time "2021-01-01" as "2006-01-02T15:04:05Z07:00": cannot parse "" as "T"`,
+			t1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := new(reader)
+			v := reflect.ValueOf(&tc.t).Elem()
+			updated, err := r.setTime(v, "Field", tc.layout, tc.val)
+
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expectedError)
+			}
+
+			assert.Equal(t, tc.expectedUpdated, updated)
+			assert.Equal(t, tc.expectedResult, tc.t)
+		})
+	}
+}
+
+func TestReaderSetStringMap(t *testing.T) {
+	tests := []struct {
+		name            string
+		m               map[string]string
+		vals            []string
+		expectedUpdated bool
+		expectedError   string
+		expectedResult  map[string]string
+	}{
+		{
+			"NewValue",
+			map[string]string{"env": "dev"}, []string{"env=prod", "team=backend"},
+			true, "",
+			map[string]string{"env": "prod", "team": "backend"},
+		},
+		{
+			"NoNewValue",
+			map[string]string{"env": "prod", "team": "backend"}, []string{"env=prod", "team=backend"},
+			false, "",
+			map[string]string{"env": "prod", "team": "backend"},
+		},
+		{
+			"EmptyValue",
+			nil, []string{"env="},
+			true, "",
+			map[string]string{"env": ""},
+		},
+		{
+			"InvalidValue",
+			map[string]string{"env": "dev"}, []string{"env"},
+			false, "invalid map entry: env",
+			map[string]string{"env": "dev"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := new(reader)
+			v := reflect.ValueOf(&tc.m).Elem()
+			updated, err := r.setStringMap(v, "Field", tc.vals)
+
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expectedError)
+			}
+
+			assert.Equal(t, tc.expectedUpdated, updated)
+			assert.Equal(t, tc.expectedResult, tc.m)
+		})
+	}
+}
+
+func TestReaderSetNamedStringMap(t *testing.T) {
+	type Key string
+	type Labels map[string]string
+
+	t.Run("NamedKey", func(t *testing.T) {
+		var m map[Key]string
+		r := new(reader)
+
+		updated, err := r.setStringMap(reflect.ValueOf(&m).Elem(), "Field", []string{"env=prod"})
+		assert.NoError(t, err)
+		assert.True(t, updated)
+		assert.Equal(t, map[Key]string{"env": "prod"}, m)
+	})
+
+	t.Run("NamedMap", func(t *testing.T) {
+		m := Labels{"env": "prod"}
+		r := new(reader)
+
+		// The same entries should not be reported as an update
+		updated, err := r.setStringMap(reflect.ValueOf(&m).Elem(), "Field", []string{"env=prod"})
+		assert.NoError(t, err)
+		assert.False(t, updated)
+
+		updated, err = r.setStringMap(reflect.ValueOf(&m).Elem(), "Field", []string{"env=dev"})
+		assert.NoError(t, err)
+		assert.True(t, updated)
+		assert.Equal(t, Labels{"env": "dev"}, m)
+	})
+
+	t.Run("NamedMapPtr", func(t *testing.T) {
+		m := &Labels{"env": "prod"}
+		r := new(reader)
+
+		updated, err := r.setStringMapPtr(reflect.ValueOf(&m).Elem(), "Field", []string{"env=prod"})
+		assert.NoError(t, err)
+		assert.False(t, updated)
+
+		updated, err = r.setStringMapPtr(reflect.ValueOf(&m).Elem(), "Field", []string{"env=dev"})
+		assert.NoError(t, err)
+		assert.True(t, updated)
+		assert.Equal(t, &Labels{"env": "dev"}, m)
+	})
+}
+
 func TestReaderSetStringPtr(t *testing.T) {
 	tests := []struct {
 		name            string
@@ -1712,6 +1866,116 @@ func TestReaderSetStructPtrRegexp(t *testing.T) {
 	}
 }
 
+func TestReaderSetTimePtr(t *testing.T) {
+	t1 := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	t2 := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name            string
+		t               *time.Time
+		layout          string
+		val             string
+		expectedUpdated bool
+		expectedError   string
+		expectedResult  *time.Time
+	}{
+		{
+			"Nil",
+			nil, time.RFC3339, "2021-01-01T00:00:00Z",
+			true, "",
+			&t2,
+		},
+		{
+			"NewValue",
+			&t1, time.RFC3339, "2021-01-01T00:00:00Z",
+			true, "",
+			&t2,
+		},
+		{
+			"NoNewValue",
+			&t2, time.DateOnly, "2021-01-01",
+			false, "",
+			&t2,
+		},
+		{
+			"InvalidValue",
+			&t1, time.DateOnly, "2021",
+			false, `parsing time "2021" as "2006-01-02": cannot parse "" as "-"`,
+			&t1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := new(reader)
+			v := reflect.ValueOf(&tc.t).Elem()
+			updated, err := r.setTimePtr(v, "Field", tc.layout, tc.val)
+
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expectedError)
+			}
+
+			assert.Equal(t, tc.expectedUpdated, updated)
+			assert.Equal(t, tc.expectedResult, tc.t)
+		})
+	}
+}
+
+func TestReaderSetStringMapPtr(t *testing.T) {
+	tests := []struct {
+		name            string
+		m               *map[string]string
+		vals            []string
+		expectedUpdated bool
+		expectedError   string
+		expectedResult  *map[string]string
+	}{
+		{
+			"Nil",
+			nil, []string{"env=prod"},
+			true, "",
+			&map[string]string{"env": "prod"},
+		},
+		{
+			"NewValue",
+			&map[string]string{"env": "dev"}, []string{"env=prod"},
+			true, "",
+			&map[string]string{"env": "prod"},
+		},
+		{
+			"NoNewValue",
+			&map[string]string{"env": "prod"}, []string{"env=prod"},
+			false, "",
+			&map[string]string{"env": "prod"},
+		},
+		{
+			"InvalidValue",
+			&map[string]string{"env": "dev"}, []string{"env:prod"},
+			false, "invalid map entry: env:prod",
+			&map[string]string{"env": "dev"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := new(reader)
+			v := reflect.ValueOf(&tc.m).Elem()
+			updated, err := r.setStringMapPtr(v, "Field", tc.vals)
+
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expectedError)
+			}
+
+			assert.Equal(t, tc.expectedUpdated, updated)
+			assert.Equal(t, tc.expectedResult, tc.m)
+		})
+	}
+}
+
 func TestReaderSetStringSlice(t *testing.T) {
 	tests := []struct {
 		name            string
@@ -2613,7 +2877,223 @@ func TestReaderSetStructSliceRegexp(t *testing.T) {
 	}
 }
 
+func TestReaderSetTimeSlice(t *testing.T) {
+	t1 := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	t2 := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name            string
+		t               []time.Time
+		layout          string
+		vals            []string
+		expectedUpdated bool
+		expectedError   string
+		expectedResult  []time.Time
+	}{
+		{
+			"Nil",
+			nil, time.RFC3339, []string{"2020-01-01T00:00:00Z", "2021-01-01T00:00:00Z"},
+			true, "",
+			[]time.Time{t1, t2},
+		},
+		{
+			"NewValue",
+			[]time.Time{t1}, time.DateOnly, []string{"2021-01-01"},
+			true, "",
+			[]time.Time{t2},
+		},
+		{
+			"NoNewValue",
+			[]time.Time{t1, t2}, time.DateOnly, []string{"2020-01-01", "2021-01-01"},
+			false, "",
+			[]time.Time{t1, t2},
+		},
+		{
+			"InvalidValue",
+			[]time.Time{t1}, time.DateOnly, []string{"2021"},
+			false, `parsing time "2021" as "2006-01-02": cannot parse "" as "-"`,
+			[]time.Time{t1},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := new(reader)
+			v := reflect.ValueOf(&tc.t).Elem()
+			updated, err := r.setTimeSlice(v, "Field", tc.layout, tc.vals)
+
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expectedError)
+			}
+
+			assert.Equal(t, tc.expectedUpdated, updated)
+			assert.Equal(t, tc.expectedResult, tc.t)
+		})
+	}
+}
+
+func TestReaderSetCustom(t *testing.T) {
+	tests := []struct {
+		name            string
+		s               byteSize
+		val             string
+		expectedUpdated bool
+		expectedError   string
+		expectedResult  byteSize
+	}{
+		{
+			"NewValue",
+			1024, "1MiB",
+			true, "",
+			1 << 20,
+		},
+		{
+			"NoNewValue",
+			1 << 20, "1MiB",
+			false, "",
+			1 << 20,
+		},
+		{
+			"InvalidValue",
+			1024, "1MB",
+			false, "invalid byte size: 1MB",
+			1024,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := new(reader)
+			v := reflect.ValueOf(&tc.s).Elem()
+			updated, err := r.setCustom(v, "Field", tc.val)
+
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expectedError)
+			}
+
+			assert.Equal(t, tc.expectedUpdated, updated)
+			assert.Equal(t, tc.expectedResult, tc.s)
+		})
+	}
+}
+
+func TestReaderSetCustomPtr(t *testing.T) {
+	l1, l2 := logLevel(0), logLevel(1)
+
+	tests := []struct {
+		name            string
+		l               *logLevel
+		val             string
+		expectedUpdated bool
+		expectedError   string
+		expectedResult  *logLevel
+	}{
+		{
+			"Nil",
+			nil, "info",
+			true, "",
+			&l2,
+		},
+		{
+			"NewValue",
+			&l1, "info",
+			true, "",
+			&l2,
+		},
+		{
+			"NoNewValue",
+			&l2, "info",
+			false, "",
+			&l2,
+		},
+		{
+			"InvalidValue",
+			&l1, "trace",
+			false, "invalid log level: trace",
+			&l1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := new(reader)
+			v := reflect.ValueOf(&tc.l).Elem()
+			updated, err := r.setCustomPtr(v, "Field", tc.val)
+
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expectedError)
+			}
+
+			assert.Equal(t, tc.expectedUpdated, updated)
+			assert.Equal(t, tc.expectedResult, tc.l)
+		})
+	}
+}
+
+func TestReaderSetCustomSlice(t *testing.T) {
+	registerTestDecoders(t)
+
+	tests := []struct {
+		name            string
+		e               []endpoint
+		vals            []string
+		expectedUpdated bool
+		expectedError   string
+		expectedResult  []endpoint
+	}{
+		{
+			"Nil",
+			nil, []string{"primary:8080", "secondary:9090"},
+			true, "",
+			[]endpoint{{"primary", 8080}, {"secondary", 9090}},
+		},
+		{
+			"NewValue",
+			[]endpoint{{"primary", 8080}}, []string{"secondary:9090"},
+			true, "",
+			[]endpoint{{"secondary", 9090}},
+		},
+		{
+			"NoNewValue",
+			[]endpoint{{"primary", 8080}}, []string{"primary:8080"},
+			false, "",
+			[]endpoint{{"primary", 8080}},
+		},
+		{
+			"InvalidValue",
+			[]endpoint{{"primary", 8080}}, []string{"primary"},
+			false, "address primary: missing port in address",
+			[]endpoint{{"primary", 8080}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := new(reader)
+			v := reflect.ValueOf(&tc.e).Elem()
+			updated, err := r.setCustomSlice(v, "Field", tc.vals)
+
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expectedError)
+			}
+
+			assert.Equal(t, tc.expectedUpdated, updated)
+			assert.Equal(t, tc.expectedResult, tc.e)
+		})
+	}
+}
+
 func TestReaderSetFieldValue(t *testing.T) {
+	registerTestDecoders(t)
+
 	type fields struct {
 		String        string
 		Bool          bool
@@ -2632,6 +3112,8 @@ func TestReaderSetFieldValue(t *testing.T) {
 		Duration      time.Duration
 		URL           url.URL
 		Regexp        regexp.Regexp
+		Time          time.Time
+		StringMap     map[string]string
 		StringPtr     *string
 		BoolPtr       *bool
 		Float32Ptr    *float32
@@ -2649,6 +3131,8 @@ func TestReaderSetFieldValue(t *testing.T) {
 		DurationPtr   *time.Duration
 		URLPtr        *url.URL
 		RegexpPtr     *regexp.Regexp
+		TimePtr       *time.Time
+		StringMapPtr  *map[string]string
 		StringSlice   []string
 		BoolSlice     []bool
 		Float32Slice  []float32
@@ -2666,6 +3150,10 @@ func TestReaderSetFieldValue(t *testing.T) {
 		DurationSlice []time.Duration
 		URLSlice      []url.URL
 		RegexpSlice   []regexp.Regexp
+		TimeSlice     []time.Time
+		ByteSize      byteSize
+		LogLevelPtr   *logLevel
+		EndpointSlice []endpoint
 	}
 
 	url1, _ := url.Parse("service-1")
@@ -2674,6 +3162,11 @@ func TestReaderSetFieldValue(t *testing.T) {
 	re1 := regexp.MustCompilePOSIX("[:digit:]")
 	re2 := regexp.MustCompilePOSIX("[:alpha:]")
 
+	t1 := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	t2 := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	info := logLevel(1)
+
 	f1 := fields{
 		String:        "old",
 		Bool:          false,
@@ -2692,6 +3185,8 @@ func TestReaderSetFieldValue(t *testing.T) {
 		Duration:      time.Second,
 		URL:           *url1,
 		Regexp:        *re1,
+		Time:          t1,
+		StringMap:     map[string]string{"key": "old"},
 		StringPtr:     ptr.String("old"),
 		BoolPtr:       ptr.Bool(false),
 		Float32Ptr:    ptr.Float32(3.1415),
@@ -2709,6 +3204,8 @@ func TestReaderSetFieldValue(t *testing.T) {
 		DurationPtr:   ptr.Duration(time.Second),
 		URLPtr:        url1,
 		RegexpPtr:     re1,
+		TimePtr:       &t1,
+		StringMapPtr:  &map[string]string{"key": "old"},
 		StringSlice:   []string{"old"},
 		BoolSlice:     []bool{false},
 		Float32Slice:  []float32{3.1415},
@@ -2726,6 +3223,10 @@ func TestReaderSetFieldValue(t *testing.T) {
 		DurationSlice: []time.Duration{time.Second},
 		URLSlice:      []url.URL{*url1, *url2},
 		RegexpSlice:   []regexp.Regexp{*re1, *re2},
+		TimeSlice:     []time.Time{t1},
+		ByteSize:      1024,
+		LogLevelPtr:   new(logLevel),
+		EndpointSlice: []endpoint{{"primary", 8080}},
 	}
 
 	f2 := fields{
@@ -2746,6 +3247,8 @@ func TestReaderSetFieldValue(t *testing.T) {
 		Duration:      time.Minute,
 		URL:           *url2,
 		Regexp:        *re2,
+		Time:          t2,
+		StringMap:     map[string]string{"key": "new"},
 		StringPtr:     ptr.String("new"),
 		BoolPtr:       ptr.Bool(true),
 		Float32Ptr:    ptr.Float32(2.7182),
@@ -2763,6 +3266,8 @@ func TestReaderSetFieldValue(t *testing.T) {
 		DurationPtr:   ptr.Duration(time.Minute),
 		URLPtr:        url2,
 		RegexpPtr:     re2,
+		TimePtr:       &t2,
+		StringMapPtr:  &map[string]string{"key": "new"},
 		StringSlice:   []string{"new"},
 		BoolSlice:     []bool{true},
 		Float32Slice:  []float32{2.7182},
@@ -2780,6 +3285,10 @@ func TestReaderSetFieldValue(t *testing.T) {
 		DurationSlice: []time.Duration{time.Minute},
 		URLSlice:      []url.URL{*url2},
 		RegexpSlice:   []regexp.Regexp{*re2},
+		TimeSlice:     []time.Time{t2},
+		ByteSize:      1 << 20,
+		LogLevelPtr:   &info,
+		EndpointSlice: []endpoint{{"secondary", 9090}},
 	}
 
 	values := map[string]string{
@@ -2800,6 +3309,8 @@ func TestReaderSetFieldValue(t *testing.T) {
 		"Duration":      "1m",
 		"URL":           "service-2",
 		"Regexp":        "[:alpha:]",
+		"Time":          "2021-01-01T00:00:00Z",
+		"StringMap":     "key=new",
 		"StringPtr":     "new",
 		"BoolPtr":       "true",
 		"Float32Ptr":    "2.7182",
@@ -2817,6 +3328,8 @@ func TestReaderSetFieldValue(t *testing.T) {
 		"DurationPtr":   "1m",
 		"URLPtr":        "service-2",
 		"RegexpPtr":     "[:alpha:]",
+		"TimePtr":       "2021-01-01T00:00:00Z",
+		"StringMapPtr":  "key=new",
 		"StringSlice":   "new",
 		"BoolSlice":     "true",
 		"Float32Slice":  "2.7182",
@@ -2834,6 +3347,10 @@ func TestReaderSetFieldValue(t *testing.T) {
 		"DurationSlice": "1m",
 		"URLSlice":      "service-2",
 		"RegexpSlice":   "[:alpha:]",
+		"TimeSlice":     "2021-01-01T00:00:00Z",
+		"ByteSize":      "1MiB",
+		"LogLevelPtr":   "info",
+		"EndpointSlice": "secondary:9090",
 	}
 
 	tests := []struct {
@@ -2872,6 +3389,7 @@ func TestReaderSetFieldValue(t *testing.T) {
 					value:   v,
 					name:    f.Name,
 					listSep: ",",
+					layout:  time.RFC3339,
 				}
 
 				updated, err := r.setFieldValue(field, tc.values[f.Name])
@@ -1,12 +1,19 @@
 package config
 
 import (
+	"bytes"
+	"errors"
 	"flag"
+	"io"
+	"log"
 	"os"
 	"reflect"
+	"syscall"
 	"testing"
+	"time"
 
 	"github.com/gardenbed/basil/ptr"
+	multierror "github.com/hashicorp/go-multierror"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -281,32 +288,230 @@ func TestReaderFromEnv(t *testing.T) {
 			},
 		},
 		{
-			name: "AllOptions",
+			name: "FromFile",
 			env: map[string]string{
-				envDebug:         "3",
-				envListSep:       "|",
-				envSkipFlag:      "true",
-				envSkipEnv:       "true",
-				envSkipFileEnv:   "true",
-				envPrefixFlag:    "config.",
-				envPrefixEnv:     "CONFIG_",
-				envPrefixFileEnv: "CONFIG_",
-				envTelepresence:  "true",
+				envFromFile: "config.yaml",
 			},
 			expectedReader: &reader{
-				debug:         3,
-				listSep:       "|",
-				skipFlag:      true,
-				skipEnv:       true,
-				skipFileEnv:   true,
-				prefixFlag:    "config.",
-				prefixEnv:     "CONFIG_",
-				prefixFileEnv: "CONFIG_",
-				telepresence:  true,
+				debug:         0,
+				listSep:       ",",
+				skipFlag:      false,
+				skipEnv:       false,
+				skipFileEnv:   false,
+				prefixFlag:    "",
+				prefixEnv:     "",
+				prefixFileEnv: "",
+				telepresence:  false,
+				docPath:       "config.yaml",
+				subscribers:   nil,
+				filesToFields: map[string]fieldInfo{},
+			},
+		},
+		{
+			name: "Lenient",
+			env: map[string]string{
+				envLenient: "true",
+			},
+			expectedReader: &reader{
+				debug:         0,
+				listSep:       ",",
+				skipFlag:      false,
+				skipEnv:       false,
+				skipFileEnv:   false,
+				prefixFlag:    "",
+				prefixEnv:     "",
+				prefixFileEnv: "",
+				telepresence:  false,
+				lenient:       true,
+				subscribers:   nil,
+				filesToFields: map[string]fieldInfo{},
+			},
+		},
+		{
+			name: "WatchDir",
+			env: map[string]string{
+				envWatchDir: "true",
+			},
+			expectedReader: &reader{
+				debug:         0,
+				listSep:       ",",
+				skipFlag:      false,
+				skipEnv:       false,
+				skipFileEnv:   false,
+				prefixFlag:    "",
+				prefixEnv:     "",
+				prefixFileEnv: "",
+				telepresence:  false,
+				watchDir:      true,
+				subscribers:   nil,
+				filesToFields: map[string]fieldInfo{},
+			},
+		},
+		{
+			name: "PollInterval",
+			env: map[string]string{
+				envPollInterval: "10s",
+			},
+			expectedReader: &reader{
+				debug:         0,
+				listSep:       ",",
+				skipFlag:      false,
+				skipEnv:       false,
+				skipFileEnv:   false,
+				prefixFlag:    "",
+				prefixEnv:     "",
+				prefixFileEnv: "",
+				telepresence:  false,
+				pollInterval:  10 * time.Second,
+				subscribers:   nil,
+				filesToFields: map[string]fieldInfo{},
+			},
+		},
+		{
+			name: "ResolveSecrets",
+			env: map[string]string{
+				envResolveSecrets: "true",
+			},
+			expectedReader: &reader{
+				debug:          0,
+				listSep:        ",",
+				skipFlag:       false,
+				skipEnv:        false,
+				skipFileEnv:    false,
+				prefixFlag:     "",
+				prefixEnv:      "",
+				prefixFileEnv:  "",
+				telepresence:   false,
+				resolveSecrets: true,
+				subscribers:    nil,
+				filesToFields:  map[string]fieldInfo{},
+			},
+		},
+		{
+			name: "EnvFile",
+			env: map[string]string{
+				envEnvFile: ".env,.env.local",
+			},
+			expectedReader: &reader{
+				debug:         0,
+				listSep:       ",",
+				skipFlag:      false,
+				skipEnv:       false,
+				skipFileEnv:   false,
+				prefixFlag:    "",
+				prefixEnv:     "",
+				prefixFileEnv: "",
+				telepresence:  false,
+				envFiles:      []string{".env", ".env.local"},
+				subscribers:   nil,
+				filesToFields: map[string]fieldInfo{},
+			},
+		},
+		{
+			name: "ExpandEnv",
+			env: map[string]string{
+				envExpandEnv: "true",
+			},
+			expectedReader: &reader{
+				debug:         0,
+				listSep:       ",",
+				skipFlag:      false,
+				skipEnv:       false,
+				skipFileEnv:   false,
+				prefixFlag:    "",
+				prefixEnv:     "",
+				prefixFileEnv: "",
+				telepresence:  false,
+				expandEnv:     true,
+				subscribers:   nil,
+				filesToFields: map[string]fieldInfo{},
+			},
+		},
+		{
+			name: "Profile",
+			env: map[string]string{
+				envProfile: "staging",
+			},
+			expectedReader: &reader{
+				debug:         0,
+				listSep:       ",",
+				skipFlag:      false,
+				skipEnv:       false,
+				skipFileEnv:   false,
+				prefixFlag:    "",
+				prefixEnv:     "",
+				prefixFileEnv: "",
+				telepresence:  false,
+				profile:       "staging",
+				subscribers:   nil,
+				filesToFields: map[string]fieldInfo{},
+			},
+		},
+		{
+			name: "Strict",
+			env: map[string]string{
+				envStrict: "true",
+			},
+			expectedReader: &reader{
+				debug:         0,
+				listSep:       ",",
+				skipFlag:      false,
+				skipEnv:       false,
+				skipFileEnv:   false,
+				prefixFlag:    "",
+				prefixEnv:     "",
+				prefixFileEnv: "",
+				telepresence:  false,
+				strict:        true,
 				subscribers:   nil,
 				filesToFields: map[string]fieldInfo{},
 			},
 		},
+		{
+			name: "AllOptions",
+			env: map[string]string{
+				envDebug:          "3",
+				envListSep:        "|",
+				envSkipFlag:       "true",
+				envSkipEnv:        "true",
+				envSkipFileEnv:    "true",
+				envPrefixFlag:     "config.",
+				envPrefixEnv:      "CONFIG_",
+				envPrefixFileEnv:  "CONFIG_",
+				envTelepresence:   "true",
+				envFromFile:       "config.yaml",
+				envLenient:        "true",
+				envWatchDir:       "true",
+				envPollInterval:   "10s",
+				envResolveSecrets: "true",
+				envEnvFile:        ".env,.env.local",
+				envExpandEnv:      "true",
+				envProfile:        "staging",
+				envStrict:         "true",
+			},
+			expectedReader: &reader{
+				debug:          3,
+				listSep:        "|",
+				skipFlag:       true,
+				skipEnv:        true,
+				skipFileEnv:    true,
+				prefixFlag:     "config.",
+				prefixEnv:      "CONFIG_",
+				prefixFileEnv:  "CONFIG_",
+				telepresence:   true,
+				docPath:        "config.yaml",
+				lenient:        true,
+				watchDir:       true,
+				pollInterval:   10 * time.Second,
+				resolveSecrets: true,
+				envFiles:       []string{".env", ".env.local"},
+				expandEnv:      true,
+				profile:        "staging",
+				strict:         true,
+				subscribers:    nil,
+				filesToFields:  map[string]fieldInfo{},
+			},
+		},
 	}
 
 	for _, tc := range tests {
@@ -399,13 +604,134 @@ func TestReaderString(t *testing.T) {
 			},
 			"Telepresence",
 		},
+		{
+			"WithFromFile",
+			&reader{
+				docPath: "config.yaml",
+			},
+			"FromFile<config.yaml>",
+		},
+		{
+			"WithLenient",
+			&reader{
+				lenient: true,
+			},
+			"Lenient",
+		},
+		{
+			"WithFlagSet",
+			&reader{
+				flagSet: flag.NewFlagSet("app", flag.ContinueOnError),
+			},
+			"FlagSet<app>",
+		},
+		{
+			"WithWatchDir",
+			&reader{
+				watchDir: true,
+			},
+			"WatchDir",
+		},
+		{
+			"WithPollInterval",
+			&reader{
+				pollInterval: 10 * time.Second,
+			},
+			"PollInterval<10s>",
+		},
+		{
+			"WithSources",
+			&reader{
+				sources: []Source{
+					mapSource{},
+				},
+			},
+			"Sources<1>",
+		},
+		{
+			"WithResolveSecrets",
+			&reader{
+				resolveSecrets: true,
+			},
+			"ResolveSecrets",
+		},
+		{
+			"WithReport",
+			&reader{
+				report: new(Report),
+			},
+			"Report",
+		},
+		{
+			"WithEnvFiles",
+			&reader{
+				envFiles: []string{".env", ".env.local"},
+			},
+			"EnvFiles<.env,.env.local>",
+		},
+		{
+			"WithExpandEnv",
+			&reader{
+				expandEnv: true,
+			},
+			"ExpandEnv",
+		},
+		{
+			"WithProfile",
+			&reader{
+				profile: "staging",
+			},
+			"Profile<staging>",
+		},
+		{
+			"WithHelp",
+			&reader{
+				help: true,
+			},
+			"Help",
+		},
+		{
+			"WithPrintConfig",
+			&reader{
+				printConfig: true,
+			},
+			"PrintConfig",
+		},
+		{
+			"WithReloadSignals",
+			&reader{
+				reloadSignals: []os.Signal{syscall.SIGHUP, syscall.SIGTERM},
+			},
+			"ReloadOnSignal<hangup,terminated>",
+		},
+		{
+			"WithValidators",
+			&reader{
+				validators: []validator{{}, {}},
+			},
+			"Validators<2>",
+		},
+		{
+			"WithStrict",
+			&reader{
+				strict: true,
+			},
+			"Strict",
+		},
+		{
+			"WithLogger",
+			&reader{
+				logger: log.New(io.Discard, "", 0),
+			},
+			"Logger",
+		},
 		{
 			"WithSubscribers",
 			&reader{
-				subscribers: []chan Update{
+				subscribers: newSubscribers([]chan Update{
 					make(chan Update),
 					make(chan Update),
-				},
+				}),
 			},
 			"Subscribers<2>",
 		},
@@ -421,12 +747,31 @@ func TestReaderString(t *testing.T) {
 				skipEnv:       true,
 				skipFileEnv:   true,
 				telepresence:  true,
-				subscribers: []chan Update{
+				docPath:       "config.yaml",
+				lenient:       true,
+				flagSet:       flag.NewFlagSet("app", flag.ContinueOnError),
+				watchDir:      true,
+				pollInterval:  10 * time.Second,
+				sources: []Source{
+					mapSource{},
+				},
+				resolveSecrets: true,
+				report:         new(Report),
+				envFiles:       []string{".env", ".env.local"},
+				expandEnv:      true,
+				profile:        "staging",
+				help:           true,
+				printConfig:    true,
+				reloadSignals:  []os.Signal{syscall.SIGHUP},
+				validators:     []validator{{}},
+				strict:         true,
+				logger:         log.New(io.Discard, "", 0),
+				subscribers: newSubscribers([]chan Update{
 					make(chan Update),
 					make(chan Update),
-				},
+				}),
 			},
-			"Debug<2> + ListSep<|> + SkipFlag + SkipEnv + SkipFileEnv + PrefixFlag<config.> + PrefixEnv<CONFIG_> + PrefixFileEnv<CONFIG_> + Telepresence + Subscribers<2>",
+			"Debug<2> + ListSep<|> + SkipFlag + SkipEnv + SkipFileEnv + PrefixFlag<config.> + PrefixEnv<CONFIG_> + PrefixFileEnv<CONFIG_> + Telepresence + FromFile<config.yaml> + Lenient + FlagSet<app> + WatchDir + PollInterval<10s> + Sources<1> + ResolveSecrets + Report + EnvFiles<.env,.env.local> + ExpandEnv + Profile<staging> + Help + PrintConfig + ReloadOnSignal<hangup> + Validators<1> + Strict + Logger + Subscribers<2>",
 		},
 	}
 
@@ -461,6 +806,16 @@ func TestReaderLog(t *testing.T) {
 			"testing ...",
 			nil,
 		},
+		{
+			"WithLogger",
+			&reader{
+				debug:  2,
+				logger: log.New(io.Discard, "", 0),
+			},
+			2,
+			"testing %s ...",
+			[]interface{}{"logger"},
+		},
 	}
 
 	for _, tc := range tests {
@@ -489,6 +844,7 @@ func TestReaderGetFieldValue(t *testing.T) {
 		fieldName, flagName, envName, fileEnvName string
 		r                                         *reader
 		expectedValue                             string
+		expectedSource                            string
 		expectFilePath                            bool
 	}{
 		{
@@ -499,6 +855,7 @@ func TestReaderGetFieldValue(t *testing.T) {
 			"Field", "-", "LOG_LEVEL", "LOG_LEVEL_FILE",
 			&reader{},
 			"info",
+			sourceEnv,
 			false,
 		},
 		{
@@ -509,6 +866,7 @@ func TestReaderGetFieldValue(t *testing.T) {
 			"Field", "-", "-", "LOG_LEVEL_FILE",
 			&reader{},
 			"error",
+			sourceFile,
 			true,
 		},
 		{
@@ -519,6 +877,7 @@ func TestReaderGetFieldValue(t *testing.T) {
 			"Field", "-", "-", "-",
 			&reader{},
 			"",
+			"",
 			false,
 		},
 		{
@@ -531,6 +890,7 @@ func TestReaderGetFieldValue(t *testing.T) {
 				skipFlag: true,
 			},
 			"info",
+			sourceEnv,
 			false,
 		},
 		{
@@ -544,6 +904,7 @@ func TestReaderGetFieldValue(t *testing.T) {
 				skipEnv:  true,
 			},
 			"error",
+			sourceFile,
 			true,
 		},
 		{
@@ -558,6 +919,7 @@ func TestReaderGetFieldValue(t *testing.T) {
 				skipFileEnv: true,
 			},
 			"",
+			"",
 			false,
 		},
 		{
@@ -568,6 +930,7 @@ func TestReaderGetFieldValue(t *testing.T) {
 			"Field", "log.level", "LOG_LEVEL", "LOG_LEVEL_FILE",
 			&reader{},
 			"debug",
+			sourceFlag,
 			false,
 		},
 		{
@@ -578,6 +941,7 @@ func TestReaderGetFieldValue(t *testing.T) {
 			"Field", "log.level", "LOG_LEVEL", "LOG_LEVEL_FILE",
 			&reader{},
 			"debug",
+			sourceFlag,
 			false,
 		},
 		{
@@ -588,6 +952,7 @@ func TestReaderGetFieldValue(t *testing.T) {
 			"Field", "log.level", "LOG_LEVEL", "LOG_LEVEL_FILE",
 			&reader{},
 			"debug",
+			sourceFlag,
 			false,
 		},
 		{
@@ -598,6 +963,7 @@ func TestReaderGetFieldValue(t *testing.T) {
 			"Field", "log.level", "LOG_LEVEL", "LOG_LEVEL_FILE",
 			&reader{},
 			"debug",
+			sourceFlag,
 			false,
 		},
 		{
@@ -608,6 +974,7 @@ func TestReaderGetFieldValue(t *testing.T) {
 			"Field", "log.level", "LOG_LEVEL", "LOG_LEVEL_FILE",
 			&reader{},
 			"info",
+			sourceEnv,
 			false,
 		},
 		{
@@ -618,6 +985,7 @@ func TestReaderGetFieldValue(t *testing.T) {
 			"Field", "log.level", "LOG_LEVEL", "LOG_LEVEL_FILE",
 			&reader{},
 			"error",
+			sourceFile,
 			true,
 		},
 		{
@@ -628,6 +996,7 @@ func TestReaderGetFieldValue(t *testing.T) {
 			"Field", "log.level", "LOG_LEVEL", "LOG_LEVEL_FILE",
 			&reader{telepresence: true},
 			"info",
+			sourceFile,
 			true,
 		},
 	}
@@ -683,8 +1052,16 @@ func TestReaderGetFieldValue(t *testing.T) {
 			}()
 
 			// Verify
-			value, filePath := tc.r.getFieldValue(tc.fieldName, tc.flagName, tc.envName, tc.fileEnvName)
+			f := fieldInfo{
+				name:        tc.fieldName,
+				flagName:    tc.flagName,
+				envName:     tc.envName,
+				fileEnvName: tc.fileEnvName,
+			}
+
+			value, source, filePath := tc.r.getFieldValue(f)
 			assert.Equal(t, tc.expectedValue, value)
+			assert.Equal(t, tc.expectedSource, source)
 			if tc.expectFilePath {
 				assert.Equal(t, tmpfile.Name(), filePath)
 			}
@@ -709,7 +1086,7 @@ func TestNotifySubscribers(t *testing.T) {
 		{
 			"NoChannel",
 			&reader{
-				subscribers: []chan Update{},
+				subscribers: newSubscribers([]chan Update{}),
 			},
 			"FieldString", "value",
 			Update{},
@@ -717,10 +1094,10 @@ func TestNotifySubscribers(t *testing.T) {
 		{
 			"WithBlockingChannels",
 			&reader{
-				subscribers: []chan Update{
+				subscribers: newSubscribers([]chan Update{
 					make(chan Update),
 					make(chan Update),
-				},
+				}),
 			},
 			"FieldInt", 27,
 			Update{"FieldInt", 27},
@@ -728,10 +1105,10 @@ func TestNotifySubscribers(t *testing.T) {
 		{
 			"WithBufferedChannels",
 			&reader{
-				subscribers: []chan Update{
+				subscribers: newSubscribers([]chan Update{
 					make(chan Update, 1),
 					make(chan Update, 1),
-				},
+				}),
 			},
 			"FieldFloat", 3.1415,
 			Update{"FieldFloat", 3.1415},
@@ -743,8 +1120,9 @@ func TestNotifySubscribers(t *testing.T) {
 			tc.r.notifySubscribers(tc.fieldName, tc.fieldValue)
 
 			if tc.expectedUpdate != (Update{}) {
-				for _, ch := range tc.r.subscribers {
-					update := <-ch
+				for _, sub := range tc.r.subscribers {
+					update, ok := sub.pop()
+					assert.True(t, ok)
 					assert.Equal(t, tc.expectedUpdate, update)
 				}
 			}
@@ -762,6 +1140,35 @@ func TestIterateOnFields(t *testing.T) {
 		IntSlice      []int
 	}
 
+	type Common struct {
+		Region string
+	}
+
+	type Node struct {
+		Name string
+		Next *Node
+	}
+
+	type nested struct {
+		Common
+		Name     string
+		Database struct {
+			Host string
+			Port int
+			Pool struct {
+				MaxSize int
+			}
+		}
+		Cache *struct {
+			Address string
+		} `flag:"redis" env:"REDIS" fileenv:"REDIS"`
+		Secrets struct {
+			Token string
+			Key   string `env:"SECRET_KEY"`
+		} `env:"-"`
+		Node Node
+	}
+
 	tests := []struct {
 		name                 string
 		r                    *reader
@@ -799,6 +1206,21 @@ func TestIterateOnFields(t *testing.T) {
 			expectedFileEnvNames: []string{"CONFIG_STRING_FILE", "CONFIG_INT_FILE", "CONFIG_STRING_POINTER_FILE", "CONFIG_INT_POINTER_FILE", "CONFIG_STRING_SLICE_FILE", "CONFIG_INT_SLICE_FILE"},
 			expectedListSeps:     []string{"|", "|", "|", "|", "|", "|"},
 		},
+		{
+			name: "NestedStructs",
+			r: &reader{
+				listSep:       ",",
+				prefixFlag:    "config.",
+				prefixEnv:     "CONFIG_",
+				prefixFileEnv: "CONFIG_",
+			},
+			s:                    &nested{},
+			expectedFieldNames:   []string{"Region", "Name", "Database.Host", "Database.Port", "Database.Pool.MaxSize", "Cache.Address", "Secrets.Token", "Secrets.Key", "Node.Name"},
+			expectedFlagNames:    []string{"config.region", "config.name", "config.database.host", "config.database.port", "config.database.pool.max.size", "redis.address", "config.secrets.token", "config.secrets.key", "config.node.name"},
+			expectedEnvNames:     []string{"CONFIG_REGION", "CONFIG_NAME", "CONFIG_DATABASE_HOST", "CONFIG_DATABASE_PORT", "CONFIG_DATABASE_POOL_MAX_SIZE", "REDIS_ADDRESS", "-", "SECRET_KEY", "CONFIG_NODE_NAME"},
+			expectedFileEnvNames: []string{"CONFIG_REGION_FILE", "CONFIG_NAME_FILE", "CONFIG_DATABASE_HOST_FILE", "CONFIG_DATABASE_PORT_FILE", "CONFIG_DATABASE_POOL_MAX_SIZE_FILE", "REDIS_ADDRESS_FILE", "CONFIG_SECRETS_TOKEN_FILE", "CONFIG_SECRETS_KEY_FILE", "CONFIG_NODE_NAME_FILE"},
+			expectedListSeps:     []string{",", ",", ",", ",", ",", ",", ",", ",", ","},
+		},
 	}
 
 	for _, tc := range tests {
@@ -813,12 +1235,12 @@ func TestIterateOnFields(t *testing.T) {
 			vStruct, err := validateStruct(tc.s)
 			assert.NoError(t, err)
 
-			tc.r.iterateOnFields(vStruct, func(v reflect.Value, fieldName, flagName, envName, fileEnvName, listSep string) {
-				fieldNames = append(fieldNames, fieldName)
-				flagNames = append(flagNames, flagName)
-				envNames = append(envNames, envName)
-				fileEnvNames = append(fileEnvNames, fileEnvName)
-				listSeps = append(listSeps, listSep)
+			tc.r.iterateOnFields(vStruct, func(f fieldInfo) {
+				fieldNames = append(fieldNames, f.name)
+				flagNames = append(flagNames, f.flagName)
+				envNames = append(envNames, f.envName)
+				fileEnvNames = append(fileEnvNames, f.fileEnvName)
+				listSeps = append(listSeps, f.listSep)
 			})
 
 			assert.Equal(t, tc.expectedFieldNames, fieldNames)
@@ -840,12 +1262,18 @@ func TestRegisterFlags(t *testing.T) {
 		IntSlice      []int
 	}
 
+	type defaults struct {
+		RetryEnabled bool          `default:"true"`
+		RetryTimeout time.Duration `default:"30s"`
+	}
+
 	tests := []struct {
-		name          string
-		r             *reader
-		s             interface{}
-		expectedError error
-		expectedFlags []string
+		name             string
+		r                *reader
+		s                interface{}
+		expectedError    error
+		expectedFlags    []string
+		expectedDefaults map[string]string
 	}{
 		{
 			name:          "Default",
@@ -863,6 +1291,17 @@ func TestRegisterFlags(t *testing.T) {
 			expectedError: nil,
 			expectedFlags: []string{"config.string", "config.int", "config.string.pointer", "config.int.pointer", "config.string.slice", "config.int.slice"},
 		},
+		{
+			name:          "WithDefaultTags",
+			r:             &reader{},
+			s:             &defaults{},
+			expectedError: nil,
+			expectedFlags: []string{"retry.enabled", "retry.timeout"},
+			expectedDefaults: map[string]string{
+				"retry.enabled": "true",
+				"retry.timeout": "30s",
+			},
+		},
 	}
 
 	for _, tc := range tests {
@@ -876,6 +1315,124 @@ func TestRegisterFlags(t *testing.T) {
 				f := flag.Lookup(expectedFlag)
 				assert.NotEmpty(t, f)
 			}
+
+			for name, expectedDefault := range tc.expectedDefaults {
+				f := flag.Lookup(name)
+				assert.Contains(t, f.Usage, "default value:\t\t\t\t"+expectedDefault+"\n")
+			}
+		})
+	}
+}
+
+func TestRegisterFlagsWithFlagSet(t *testing.T) {
+	type fields struct {
+		TracingEnabled bool
+		TracingSampler string `default:"always"`
+		TracingSkipped string `flag:"-"`
+	}
+
+	fs := flag.NewFlagSet("app", flag.ContinueOnError)
+	r := &reader{
+		flagSet: fs,
+	}
+
+	vStruct, err := validateStruct(&fields{})
+	assert.NoError(t, err)
+
+	r.registerFlags(vStruct)
+
+	tracingEnabled := fs.Lookup("tracing.enabled")
+	assert.NotNil(t, tracingEnabled)
+	assert.IsType(t, &fieldFlag{}, tracingEnabled.Value)
+
+	tracingSampler := fs.Lookup("tracing.sampler")
+	assert.NotNil(t, tracingSampler)
+	assert.Contains(t, tracingSampler.Usage, "default value:\t\t\t\talways\n")
+
+	assert.Nil(t, fs.Lookup("tracing.skipped"))
+
+	// The global flag set should be left untouched
+	assert.Nil(t, flag.Lookup("tracing.enabled"))
+	assert.Nil(t, flag.Lookup("tracing.sampler"))
+
+	// Registering the same flags again should not panic
+	r.registerFlags(vStruct)
+}
+
+func TestParseFlags(t *testing.T) {
+	type fields struct {
+		Enabled bool
+		Port    uint16
+	}
+
+	tests := []struct {
+		name               string
+		args               []string
+		parsed             bool
+		expectedError      string
+		expectedFlagValues map[string]string
+	}{
+		{
+			name:               "NoFlag",
+			args:               []string{},
+			expectedFlagValues: map[string]string{},
+		},
+		{
+			name: "Valid",
+			args: []string{"-enabled", "--port", "8080", "extra"},
+			expectedFlagValues: map[string]string{
+				"enabled": "true",
+				"port":    "8080",
+			},
+		},
+		{
+			name:          "InvalidValue",
+			args:          []string{"-port=http"},
+			expectedError: `invalid value "http" for flag -port: strconv.ParseUint: parsing "http": invalid syntax`,
+		},
+		{
+			name:          "UnknownFlag",
+			args:          []string{"-portal=8080"},
+			expectedError: "flag provided but not defined: -portal",
+		},
+		{
+			name:   "AlreadyParsed",
+			args:   []string{"-port=8080"},
+			parsed: true,
+			expectedFlagValues: map[string]string{
+				"port": "8080",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			fs := flag.NewFlagSet("app", flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+
+			r := &reader{
+				flagSet:  fs,
+				flagArgs: tc.args,
+			}
+
+			vStruct, err := validateStruct(&fields{})
+			assert.NoError(t, err)
+
+			r.registerFlags(vStruct)
+
+			if tc.parsed {
+				assert.NoError(t, fs.Parse(tc.args))
+				r.flagArgs = []string{"-port=9090"}
+			}
+
+			err = r.parseFlags()
+
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedFlagValues, r.flagValues)
+			} else {
+				assert.EqualError(t, err, tc.expectedError)
+			}
 		})
 	}
 }
@@ -900,6 +1457,25 @@ func TestReadFields(t *testing.T) {
 		IntSlice      []int
 	}
 
+	type nested struct {
+		Database struct {
+			Host string
+			Port int
+		}
+		Cache *struct {
+			Address string
+		}
+	}
+
+	type defaults struct {
+		String        string            `default:"content"`
+		Bool          bool              `default:"true"`
+		Duration      time.Duration     `default:"30s"`
+		StringPointer *string           `default:"content"`
+		IntSlice      []int             `default:"1|2|3" sep:"|"`
+		StringMap     map[string]string `default:"env=prod,team=backend"`
+	}
+
 	tests := []struct {
 		name     string
 		args     []string
@@ -1025,6 +1601,118 @@ func TestReadFields(t *testing.T) {
 				IntSlice:      []int{-9223372036854775808},
 			},
 		},
+		{
+			"AllFromDocument",
+			[]string{"app"},
+			[]env{},
+			[]file{},
+			&reader{
+				listSep: ",",
+				doc: &document{
+					format: formatYAML,
+					data: map[string]interface{}{
+						"string":         "content",
+						"int":            -9223372036854775808,
+						"string_pointer": "content",
+						"int_pointer":    -9223372036854775808,
+						"string_slice":   []interface{}{"content"},
+						"int_slice":      []interface{}{-9223372036854775808},
+					},
+				},
+				filesToFields: map[string]fieldInfo{},
+			},
+			&fields{},
+			&fields{
+				String:        "content",
+				Int:           -9223372036854775808,
+				StringPointer: ptr.String("content"),
+				IntPointer:    ptr.Int(-9223372036854775808),
+				StringSlice:   []string{"content"},
+				IntSlice:      []int{-9223372036854775808},
+			},
+		},
+		{
+			"NestedFromFlagsEnvVarsAndDocument",
+			[]string{"app", "-database.port=5432"},
+			[]env{
+				{"DATABASE_HOST", "localhost"},
+			},
+			[]file{},
+			&reader{
+				listSep: ",",
+				doc: &document{
+					format: formatYAML,
+					data: map[string]interface{}{
+						"database": map[string]interface{}{
+							"host": "postgres",
+							"port": 3306,
+						},
+						"cache": map[string]interface{}{
+							"address": "redis:6379",
+						},
+					},
+				},
+				filesToFields: map[string]fieldInfo{},
+			},
+			&nested{},
+			&nested{
+				Database: struct {
+					Host string
+					Port int
+				}{
+					Host: "localhost",
+					Port: 5432,
+				},
+				Cache: &struct {
+					Address string
+				}{
+					Address: "redis:6379",
+				},
+			},
+		},
+		{
+			"AllFromDefaultTags",
+			[]string{"app"},
+			[]env{},
+			[]file{},
+			&reader{
+				listSep:       ",",
+				filesToFields: map[string]fieldInfo{},
+			},
+			&defaults{},
+			&defaults{
+				String:        "content",
+				Bool:          true,
+				Duration:      30 * time.Second,
+				StringPointer: ptr.String("content"),
+				IntSlice:      []int{1, 2, 3},
+				StringMap:     map[string]string{"env": "prod", "team": "backend"},
+			},
+		},
+		{
+			"DefaultTagsWithEnvVars",
+			[]string{"app"},
+			[]env{
+				{"STRING", "value"},
+				{"DURATION", "1m"},
+			},
+			[]file{},
+			&reader{
+				listSep:       ",",
+				filesToFields: map[string]fieldInfo{},
+			},
+			&defaults{
+				Bool: false,
+			},
+			&defaults{
+				String:        "value",
+				Bool:          true,
+				Duration:      time.Minute,
+				StringPointer: ptr.String("content"),
+				IntSlice:      []int{1, 2, 3},
+				StringMap:     map[string]string{"env": "prod", "team": "backend"},
+			},
+		},
 		{
 			"WithTelepresenceOption",
 			[]string{"app"},
@@ -1109,8 +1797,298 @@ func TestReadFields(t *testing.T) {
 			vStruct, err := validateStruct(tc.s)
 			assert.NoError(t, err)
 
-			tc.r.readFields(vStruct)
+			err = tc.r.readFields(vStruct)
+			assert.NoError(t, err)
 			assert.Equal(t, tc.expected, tc.s)
 		})
 	}
 }
+
+func TestReadFieldsInvalid(t *testing.T) {
+	type fields struct {
+		String      string
+		Int         int
+		Bool        bool
+		Float64     float64
+		IntSlice    []int
+		BoolPointer *bool
+		Timeout     time.Duration `default:"thirty"`
+	}
+
+	tests := []struct {
+		name           string
+		args           []string
+		env            map[string]string
+		r              *reader
+		expectedErrors []string
+		expected       *fields
+	}{
+		{
+			name: "InvalidValues",
+			args: []string{"app", "-int=NaN"},
+			env: map[string]string{
+				"STRING":       "content",
+				"BOOL":         "yes",
+				"FLOAT64":      "3.14",
+				"INT_SLICE":    "1,two,3",
+				"BOOL_POINTER": "true",
+			},
+			r: &reader{
+				listSep:       ",",
+				filesToFields: map[string]fieldInfo{},
+			},
+			expectedErrors: []string{
+				`invalid value "NaN" for Int from flag`,
+				`invalid value "yes" for Bool from env`,
+				`invalid value "1,two,3" for IntSlice from env`,
+				`invalid value "thirty" for Timeout from default`,
+			},
+			expected: &fields{
+				String:      "content",
+				Float64:     3.14,
+				BoolPointer: ptr.Bool(true),
+			},
+		},
+		{
+			name: "InvalidValuesWithLenientOption",
+			args: []string{"app", "-int=NaN"},
+			env: map[string]string{
+				"STRING":       "content",
+				"BOOL":         "yes",
+				"FLOAT64":      "3.14",
+				"INT_SLICE":    "1,two,3",
+				"BOOL_POINTER": "true",
+			},
+			r: &reader{
+				listSep:       ",",
+				lenient:       true,
+				filesToFields: map[string]fieldInfo{},
+			},
+			expectedErrors: nil,
+			expected: &fields{
+				String:      "content",
+				Float64:     3.14,
+				BoolPointer: ptr.Bool(true),
+			},
+		},
+	}
+
+	origArgs := os.Args
+	defer func() {
+		os.Args = origArgs
+	}()
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			os.Args = tc.args
+
+			for name, value := range tc.env {
+				err := os.Setenv(name, value)
+				assert.NoError(t, err)
+
+				defer func() {
+					assert.NoError(t, os.Unsetenv(name))
+				}()
+			}
+
+			s := new(fields)
+			vStruct, err := validateStruct(s)
+			assert.NoError(t, err)
+
+			err = tc.r.readFields(vStruct)
+			assert.Equal(t, tc.expected, s)
+
+			if len(tc.expectedErrors) == 0 {
+				assert.NoError(t, err)
+			} else {
+				var merr *multierror.Error
+				assert.True(t, errors.As(err, &merr))
+				assert.Len(t, merr.Errors, len(tc.expectedErrors))
+
+				for i, expectedError := range tc.expectedErrors {
+					var ferr *FieldError
+					assert.True(t, errors.As(merr.Errors[i], &ferr))
+					assert.Contains(t, ferr.Error(), expectedError)
+					assert.Error(t, ferr.Unwrap())
+				}
+			}
+		})
+	}
+}
+
+func TestValidateFields(t *testing.T) {
+	type fields struct {
+		Name     string `required:"true"`
+		Port     int    `min:"1024" max:"65535"`
+		LogLevel string `oneof:"debug|info"`
+		Database struct {
+			Host string `required:"true" pattern:"^[a-z.]+$"`
+		}
+	}
+
+	tests := []struct {
+		name           string
+		s              *fields
+		expectedErrors []string
+	}{
+		{
+			name: "Valid",
+			s: &fields{
+				Name:     "app",
+				Port:     8080,
+				LogLevel: "info",
+				Database: struct {
+					Host string `required:"true" pattern:"^[a-z.]+$"`
+				}{
+					Host: "postgres.local",
+				},
+			},
+			expectedErrors: nil,
+		},
+		{
+			name: "Invalid",
+			s: &fields{
+				Port:     80,
+				LogLevel: "trace",
+			},
+			expectedErrors: []string{
+				"Name failed required validation: value is required",
+				"Port failed min validation: value must be at least 1024",
+				`LogLevel failed oneof validation: "trace" is not one of debug, info`,
+				"Database.Host failed required validation: value is required",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &reader{}
+			vStruct, err := validateStruct(tc.s)
+			assert.NoError(t, err)
+
+			err = r.validateFields(vStruct)
+
+			if len(tc.expectedErrors) == 0 {
+				assert.NoError(t, err)
+			} else {
+				var merr *multierror.Error
+				assert.True(t, errors.As(err, &merr))
+				assert.Len(t, merr.Errors, len(tc.expectedErrors))
+
+				for i, expectedError := range tc.expectedErrors {
+					var verr *ValidationError
+					assert.True(t, errors.As(merr.Errors[i], &verr))
+					assert.EqualError(t, verr, expectedError)
+				}
+			}
+		})
+	}
+}
+
+func TestUpdateField(t *testing.T) {
+	tests := []struct {
+		name            string
+		value           int
+		val             string
+		expectedError   string
+		expectedValue   int
+		expectedUpdates int
+	}{
+		{"Valid", 8080, "9090", "", 9090, 1},
+		{"Unchanged", 8080, "8080", "", 8080, 0},
+		{"Invalid", 8080, "port", `strconv.ParseInt: parsing "port": invalid syntax`, 8080, 0},
+		{"FailedValidation", 8080, "80", "Port failed min validation: value must be at least 1024", 8080, 0},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &reader{
+				subscribers: newSubscribers([]chan Update{
+					make(chan Update),
+				}),
+			}
+
+			port := tc.value
+			f := fieldInfo{
+				value:      reflect.ValueOf(&port).Elem(),
+				name:       "Port",
+				validation: validation{min: "1024"},
+			}
+
+			changed, err := r.updateField(f, tc.val)
+
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expectedError)
+			}
+
+			assert.Equal(t, tc.expectedUpdates > 0, changed)
+			assert.Equal(t, tc.expectedValue, port)
+
+			update, ok := r.subscribers[0].pop()
+			if tc.expectedUpdates > 0 {
+				assert.True(t, ok)
+				assert.Equal(t, Update{"Port", tc.expectedValue}, update)
+			} else {
+				assert.False(t, ok)
+			}
+		})
+	}
+}
+
+func TestReadFieldsMasksSecrets(t *testing.T) {
+	type masked struct {
+		MaskedUser     string
+		MaskedPassword string
+		MaskedKey      string `secret:"true" default:"k3y"`
+	}
+
+	assert.NoError(t, os.Setenv("MASKED_USER", "admin"))
+	assert.NoError(t, os.Setenv("MASKED_PASSWORD", "s3cr3t"))
+	defer func() {
+		assert.NoError(t, os.Unsetenv("MASKED_USER"))
+		assert.NoError(t, os.Unsetenv("MASKED_PASSWORD"))
+	}()
+
+	origArgs := os.Args
+	defer func() {
+		os.Args = origArgs
+	}()
+
+	os.Args = []string{"app"}
+
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	r := &reader{
+		debug: 5,
+		subscribers: newSubscribers([]chan Update{
+			make(chan Update),
+		}),
+		filesToFields: map[string]fieldInfo{},
+	}
+
+	m := new(masked)
+	err := r.readFields(reflect.ValueOf(m).Elem())
+	assert.NoError(t, err)
+
+	assert.Equal(t, "admin", m.MaskedUser)
+	assert.Equal(t, "s3cr3t", m.MaskedPassword)
+	assert.Equal(t, "k3y", m.MaskedKey)
+
+	// Secrets should be masked in logs
+	assert.Contains(t, buf.String(), "admin")
+	assert.NotContains(t, buf.String(), "s3cr3t")
+	assert.NotContains(t, buf.String(), "k3y")
+
+	// Secrets should be masked in updates
+	expectedUpdates := []Update{
+		{"MaskedUser", "admin"},
+		{"MaskedPassword", "*****"},
+		{"MaskedKey", "*****"},
+	}
+
+	assert.Equal(t, expectedUpdates, r.subscribers[0].queue)
+}
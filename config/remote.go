@@ -0,0 +1,272 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	sourceHTTP = "http"
+	sourceKV   = "kv"
+
+	defaultRefreshInterval = 30 * time.Second
+)
+
+// SourceOption sets optional parameters for remote sources.
+type SourceOption func(*RemoteSource)
+
+// SourceHTTPClient is the option for using a custom HTTP client for fetching values.
+func SourceHTTPClient(client *http.Client) SourceOption {
+	return func(s *RemoteSource) {
+		s.client = client
+	}
+}
+
+// SourceHeader is the option for adding a header (e.g. an authorization token) to every request for fetching values.
+func SourceHeader(key, value string) SourceOption {
+	return func(s *RemoteSource) {
+		s.header.Add(key, value)
+	}
+}
+
+// SourceRefreshInterval is the option for setting the interval at which values are fetched again when watching.
+// The default interval is 30 seconds.
+func SourceRefreshInterval(interval time.Duration) SourceOption {
+	return func(s *RemoteSource) {
+		s.interval = interval
+	}
+}
+
+// RemoteSource is a source reading values from a remote server over HTTP.
+// Values are fetched once when the source is created and every refresh interval when watching.
+// Values of nested fields are looked up the same way as a configuration document (see FromFile option).
+type RemoteSource struct {
+	name     string
+	fetch    func(ctx context.Context) (*document, []byte, error)
+	client   *http.Client
+	header   http.Header
+	interval time.Duration
+
+	mu   sync.RWMutex
+	doc  *document
+	hash [sha256.Size]byte
+}
+
+func newRemoteSource(name string, opts []SourceOption) *RemoteSource {
+	s := &RemoteSource{
+		name:     name,
+		client:   &http.Client{Timeout: 10 * time.Second},
+		header:   http.Header{},
+		interval: defaultRefreshInterval,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// NewHTTPSource creates a source for reading values from a JSON document served by an HTTP endpoint.
+func NewHTTPSource(ctx context.Context, endpoint string, opts ...SourceOption) (*RemoteSource, error) {
+	s := newRemoteSource(sourceHTTP, opts)
+
+	s.fetch = func(ctx context.Context) (*document, []byte, error) {
+		b, _, err := s.get(ctx, endpoint)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		doc, err := parseDocument(formatJSON, b)
+		if err != nil {
+			return nil, nil, fmt.Errorf("cannot parse %s: %s", endpoint, err)
+		}
+
+		return doc, b, nil
+	}
+
+	if _, err := s.refresh(ctx); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// kvPair is a key-value pair returned by the KV HTTP API.
+type kvPair struct {
+	Key   string
+	Value []byte
+}
+
+// NewKVSource creates a source for reading values from a key-value store compatible with the Consul KV HTTP API.
+// All keys under the given prefix are read and the path to each field is mapped to a key separated by slashes.
+//
+//	Log.Level  -->  <prefix>/log/level
+func NewKVSource(ctx context.Context, addr, prefix string, opts ...SourceOption) (*RemoteSource, error) {
+	s := newRemoteSource(sourceKV, opts)
+
+	prefix = strings.Trim(prefix, "/")
+	endpoint, err := url.JoinPath(addr, "v1", "kv", prefix)
+	if err != nil {
+		return nil, err
+	}
+	endpoint += "?recurse=true"
+
+	s.fetch = func(ctx context.Context) (*document, []byte, error) {
+		b, status, err := s.get(ctx, endpoint)
+		if err != nil && status != http.StatusNotFound {
+			return nil, nil, err
+		}
+
+		// No key exists under the prefix
+		if status == http.StatusNotFound {
+			return &document{format: formatJSON, data: map[string]interface{}{}}, nil, nil
+		}
+
+		pairs := []kvPair{}
+		if err := json.Unmarshal(b, &pairs); err != nil {
+			return nil, nil, fmt.Errorf("cannot parse %s: %s", endpoint, err)
+		}
+
+		data := map[string]interface{}{}
+		for _, p := range pairs {
+			// Keys under other prefixes with the same beginning (i.e. application/... for app) are skipped
+			key, ok := strings.CutPrefix(p.Key, prefix)
+			if !ok || (prefix != "" && key != "" && key[0] != '/') {
+				continue
+			}
+
+			key = strings.Trim(key, "/")
+			if key == "" || p.Value == nil {
+				continue
+			}
+			setNestedKey(data, strings.Split(key, "/"), string(p.Value))
+		}
+
+		return &document{format: formatJSON, data: data}, b, nil
+	}
+
+	if _, err := s.refresh(ctx); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// setNestedKey sets a value in nested maps creating the intermediate maps as needed.
+func setNestedKey(m map[string]interface{}, keys []string, val string) {
+	for _, key := range keys[:len(keys)-1] {
+		next, ok := m[key].(map[string]interface{})
+		if !ok {
+			next = map[string]interface{}{}
+			m[key] = next
+		}
+		m = next
+	}
+
+	// A key with nested keys takes precedence
+	if _, ok := m[keys[len(keys)-1]].(map[string]interface{}); !ok {
+		m[keys[len(keys)-1]] = val
+	}
+}
+
+// get sends a GET request and returns the response body and status code.
+func (s *RemoteSource) get(ctx context.Context, endpoint string) ([]byte, int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	req.Header = s.header.Clone()
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, resp.StatusCode, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, resp.StatusCode, fmt.Errorf("unexpected status from %s: %s: %s", endpoint, resp.Status, bytes.TrimSpace(b))
+	}
+
+	return b, resp.StatusCode, nil
+}
+
+// refresh fetches the values again and reports whether they are changed.
+func (s *RemoteSource) refresh(ctx context.Context) (bool, error) {
+	doc, b, err := s.fetch(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	hash := sha256.Sum256(b)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.doc != nil && s.hash == hash {
+		return false, nil
+	}
+
+	s.doc, s.hash = doc, hash
+
+	return true, nil
+}
+
+// Name returns the name of the source.
+func (s *RemoteSource) Name() string {
+	return s.name
+}
+
+// Lookup returns the value of a field in the source.
+func (s *RemoteSource) Lookup(key Key) (string, bool) {
+	if len(key.Path) == 0 {
+		return "", false
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.doc.lookup(key.Path, key.listSep, key.layout)
+}
+
+// Watch fetches the values every refresh interval and calls notify when they are changed.
+// If the values cannot be fetched, the error is reported and the last fetched values are kept.
+func (s *RemoteSource) Watch(ctx context.Context, notify func(), report func(error)) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			changed, err := s.refresh(ctx)
+			if err != nil {
+				// Requests cancelled by stopping are not errors
+				if ctx.Err() == nil {
+					report(fmt.Errorf("cannot refresh %s source: %s", s.name, err))
+				}
+				continue
+			}
+
+			if changed {
+				notify()
+			}
+		}
+	}
+}
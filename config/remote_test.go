@@ -0,0 +1,263 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// kvServer is a stand-in for a key-value store serving the Consul KV HTTP API.
+type kvServer struct {
+	sync.Mutex
+	pairs  map[string]string
+	status int
+}
+
+func (s *kvServer) set(key, val string) {
+	s.Lock()
+	defer s.Unlock()
+	s.pairs[key] = val
+}
+
+func (s *kvServer) fail(status int) {
+	s.Lock()
+	defer s.Unlock()
+	s.status = status
+}
+
+func (s *kvServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.status != 0 {
+		w.WriteHeader(s.status)
+		return
+	}
+
+	prefix := strings.TrimPrefix(r.URL.Path, "/v1/kv/")
+	if r.URL.Query().Get("recurse") != "true" || r.Header.Get("X-Consul-Token") != "token" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	pairs := []kvPair{}
+	for key, val := range s.pairs {
+		if strings.HasPrefix(key, prefix) {
+			pairs = append(pairs, kvPair{Key: key, Value: []byte(val)})
+		}
+	}
+
+	if len(pairs) == 0 {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(pairs)
+}
+
+func TestSetNestedKey(t *testing.T) {
+	m := map[string]interface{}{}
+	setNestedKey(m, []string{"log", "level"}, "debug")
+	setNestedKey(m, []string{"port"}, "8080")
+	setNestedKey(m, []string{"log", "level", "nested"}, "info")
+
+	expected := map[string]interface{}{
+		"log": map[string]interface{}{
+			"level": map[string]interface{}{
+				"nested": "info",
+			},
+		},
+		"port": "8080",
+	}
+
+	assert.Equal(t, expected, m)
+}
+
+func TestNewHTTPSource(t *testing.T) {
+	var body atomic.Value
+	body.Store(`{ "log": { "level": "info", "tags": ["a", "b"] }, "port": 8080 }`)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/config":
+			if r.Header.Get("Authorization") != "Bearer token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte(body.Load().(string)))
+		case "/invalid":
+			_, _ = w.Write([]byte(`[`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+
+	ctx := context.Background()
+
+	t.Run("NotFound", func(t *testing.T) {
+		s, err := NewHTTPSource(ctx, ts.URL+"/missing")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "unexpected status from "+ts.URL+"/missing: 404 Not Found")
+		assert.Nil(t, s)
+	})
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		s, err := NewHTTPSource(ctx, ts.URL+"/config")
+		assert.Error(t, err)
+		assert.Nil(t, s)
+	})
+
+	t.Run("InvalidJSON", func(t *testing.T) {
+		s, err := NewHTTPSource(ctx, ts.URL+"/invalid")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "cannot parse "+ts.URL+"/invalid")
+		assert.Nil(t, s)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		s, err := NewHTTPSource(ctx, ts.URL+"/config",
+			SourceHeader("Authorization", "Bearer token"),
+			SourceRefreshInterval(10*time.Millisecond),
+		)
+		assert.NoError(t, err)
+		assert.Equal(t, "http", s.Name())
+
+		tests := []struct {
+			key           Key
+			expectedValue string
+			expectedOK    bool
+		}{
+			{Key{Path: []string{"log", "level"}}, "info", true},
+			{Key{Path: []string{"log", "tags"}, listSep: ","}, "a,b", true},
+			{Key{Path: []string{"port"}}, "8080", true},
+			{Key{Path: []string{"missing"}}, "", false},
+			{Key{}, "", false},
+		}
+
+		for _, tc := range tests {
+			value, ok := s.Lookup(tc.key)
+			assert.Equal(t, tc.expectedValue, value)
+			assert.Equal(t, tc.expectedOK, ok)
+		}
+
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		notified := make(chan struct{}, 10)
+		go s.Watch(ctx, func() {
+			notified <- struct{}{}
+		}, func(err error) {
+			t.Errorf("unexpected error: %s", err)
+		})
+
+		body.Store(`{ "log": { "level": "debug" } }`)
+
+		select {
+		case <-notified:
+		case <-time.After(2 * time.Second):
+			t.Fatal("no notification received")
+		}
+
+		value, ok := s.Lookup(Key{Path: []string{"log", "level"}})
+		assert.True(t, ok)
+		assert.Equal(t, "debug", value)
+	})
+}
+
+func TestNewKVSource(t *testing.T) {
+	kv := &kvServer{
+		pairs: map[string]string{
+			"app/log/level": "info",
+			"app/port":      "8080",
+			"application/x": "value",
+			"other/port":    "9090",
+		},
+	}
+
+	ts := httptest.NewServer(kv)
+	defer ts.Close()
+
+	ctx := context.Background()
+
+	t.Run("BadRequest", func(t *testing.T) {
+		s, err := NewKVSource(ctx, ts.URL, "app")
+		assert.Error(t, err)
+		assert.Nil(t, s)
+	})
+
+	t.Run("EmptyPrefix", func(t *testing.T) {
+		s, err := NewKVSource(ctx, ts.URL, "missing", SourceHeader("X-Consul-Token", "token"))
+		assert.NoError(t, err)
+
+		_, ok := s.Lookup(Key{Path: []string{"port"}})
+		assert.False(t, ok)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		s, err := NewKVSource(ctx, ts.URL+"/", "/app/",
+			SourceHTTPClient(ts.Client()),
+			SourceHeader("X-Consul-Token", "token"),
+			SourceRefreshInterval(10*time.Millisecond),
+		)
+		assert.NoError(t, err)
+		assert.Equal(t, "kv", s.Name())
+
+		value, ok := s.Lookup(Key{Path: []string{"Log", "Level"}})
+		assert.True(t, ok)
+		assert.Equal(t, "info", value)
+
+		value, ok = s.Lookup(Key{Path: []string{"port"}})
+		assert.True(t, ok)
+		assert.Equal(t, "8080", value)
+
+		// Keys under other prefixes with the same beginning should be skipped
+		_, ok = s.Lookup(Key{Path: []string{"lication", "x"}})
+		assert.False(t, ok)
+
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		notified := make(chan struct{}, 10)
+		reported := make(chan error, 10)
+		go s.Watch(ctx, func() {
+			notified <- struct{}{}
+		}, func(err error) {
+			reported <- err
+		})
+
+		kv.set("app/log/level", "debug")
+
+		select {
+		case <-notified:
+		case <-time.After(2 * time.Second):
+			t.Fatal("no notification received")
+		}
+
+		value, ok = s.Lookup(Key{Path: []string{"log", "level"}})
+		assert.True(t, ok)
+		assert.Equal(t, "debug", value)
+
+		// Errors fetching values should be reported and the last values should be kept
+		kv.fail(http.StatusInternalServerError)
+
+		select {
+		case err := <-reported:
+			assert.Contains(t, err.Error(), "cannot refresh kv source: unexpected status from")
+		case <-time.After(2 * time.Second):
+			t.Fatal("no error reported")
+		}
+
+		value, ok = s.Lookup(Key{Path: []string{"log", "level"}})
+		assert.True(t, ok)
+		assert.Equal(t, "debug", value)
+	})
+}
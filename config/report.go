@@ -0,0 +1,125 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"text/tabwriter"
+)
+
+// FieldReport records how the value of a field is read.
+type FieldReport struct {
+	// Field is the name of the field (i.e. Log.Level).
+	Field string `json:"field"`
+	// Flag is the command-line flag name for the field.
+	Flag string `json:"flag,omitempty"`
+	// Env is the environment variable name for the field.
+	Env string `json:"env,omitempty"`
+	// FileEnv is the name of the environment variable holding a file path for the field.
+	FileEnv string `json:"fileEnv,omitempty"`
+	// Source is the source from which the value is read (flag, env, file, document, default, or the name of an additional source).
+	Source string `json:"source"`
+	// File is the path to the file from which the value is read if the source is file.
+	File string `json:"file,omitempty"`
+	// Default is true if no value is read for the field from any source other than defaults.
+	Default bool `json:"default"`
+	// Value is the current value of the field. Values of fields holding secrets are masked.
+	Value string `json:"value"`
+}
+
+// Report records how the values of all fields are read.
+// A report can be filled by passing it to Pick or Watch using the WithReport option.
+// When watching, the report is updated as new values are set on fields.
+// A report is safe for concurrent use, and it can be printed as a table or served as JSON (i.e. on a /debug/config endpoint).
+type Report struct {
+	mu     sync.RWMutex
+	fields []FieldReport
+	index  map[string]int
+}
+
+// set adds or updates the report for a field.
+func (r *Report) set(fr FieldReport) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.index == nil {
+		r.index = map[string]int{}
+	}
+
+	if i, ok := r.index[fr.Field]; ok {
+		r.fields[i] = fr
+	} else {
+		r.index[fr.Field] = len(r.fields)
+		r.fields = append(r.fields, fr)
+	}
+}
+
+// Fields returns the reports for all fields in the order of fields.
+func (r *Report) Fields() []FieldReport {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]FieldReport{}, r.fields...)
+}
+
+// String returns the report as a table.
+func (r *Report) String() string {
+	buf := new(bytes.Buffer)
+	tw := tabwriter.NewWriter(buf, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "FIELD\tVALUE\tSOURCE\tFLAG\tENV\tFILE ENV\tFILE")
+
+	for _, fr := range r.Fields() {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", fr.Field, fr.Value, fr.Source, fr.Flag, fr.Env, fr.FileEnv, fr.File)
+	}
+
+	_ = tw.Flush()
+
+	return buf.String()
+}
+
+// MarshalJSON returns the report as a JSON array of field reports.
+func (r *Report) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.Fields())
+}
+
+// ServeHTTP serves the report as JSON.
+func (r *Report) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	b, err := r.MarshalJSON()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(b)
+}
+
+// reportField records how the value of a field is read if a report is requested.
+// The raw value is the value read from the source before being resolved.
+func (r *reader) reportField(f fieldInfo, source, filePath, raw string) {
+	if r.report == nil {
+		return
+	}
+
+	key := f.getKey()
+
+	if source == "" {
+		source = sourceDefault
+	}
+
+	val := formatValue(f.value, f.listSep, f.layout)
+	val = maskValue(f.secret || r.isReference(raw), val)
+
+	r.report.set(FieldReport{
+		Field:   f.name,
+		Flag:    key.Flag,
+		Env:     key.Env,
+		FileEnv: key.FileEnv,
+		Source:  source,
+		File:    filePath,
+		Default: source == sourceDefault,
+		Value:   val,
+	})
+}
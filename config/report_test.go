@@ -0,0 +1,133 @@
+package config
+
+import (
+	"context"
+	"flag"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReport(t *testing.T) {
+	r := new(Report)
+	assert.Empty(t, r.Fields())
+
+	r.set(FieldReport{Field: "Port", Env: "PORT", Source: "env", Value: "8080"})
+	r.set(FieldReport{Field: "Level", Source: "default", Default: true, Value: "info"})
+	r.set(FieldReport{Field: "Port", Env: "PORT", FileEnv: "PORT_FILE", Source: "file", File: "/etc/port", Value: "9090"})
+
+	expectedFields := []FieldReport{
+		{Field: "Port", Env: "PORT", FileEnv: "PORT_FILE", Source: "file", File: "/etc/port", Value: "9090"},
+		{Field: "Level", Source: "default", Default: true, Value: "info"},
+	}
+
+	assert.Equal(t, expectedFields, r.Fields())
+
+	t.Run("String", func(t *testing.T) {
+		expected := "" +
+			"FIELD  VALUE  SOURCE   FLAG  ENV   FILE ENV   FILE\n" +
+			"Port   9090   file           PORT  PORT_FILE  /etc/port\n" +
+			"Level  info   default                         \n"
+
+		assert.Equal(t, expected, r.String())
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		expected := `[
+			{ "field": "Port", "env": "PORT", "fileEnv": "PORT_FILE", "source": "file", "file": "/etc/port", "default": false, "value": "9090" },
+			{ "field": "Level", "source": "default", "default": true, "value": "info" }
+		]`
+
+		b, err := r.MarshalJSON()
+		assert.NoError(t, err)
+		assert.JSONEq(t, expected, string(b))
+
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest("GET", "/debug/config", nil))
+		assert.Equal(t, 200, rec.Code)
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+		assert.JSONEq(t, expected, rec.Body.String())
+	})
+}
+
+func TestPickWithReport(t *testing.T) {
+	type reported struct {
+		ReportName     string `default:"app"`
+		ReportPort     int
+		ReportLevel    string
+		ReportPassword string
+		ReportTimeout  time.Duration `flag:"-"`
+	}
+
+	path := filepath.Join(t.TempDir(), "password")
+	assert.NoError(t, os.WriteFile(path, []byte("s3cr3t"), 0644))
+
+	assert.NoError(t, os.Setenv("REPORT_PORT", "8080"))
+	assert.NoError(t, os.Setenv("REPORT_PASSWORD_FILE", path))
+	defer func() {
+		assert.NoError(t, os.Unsetenv("REPORT_PORT"))
+		assert.NoError(t, os.Unsetenv("REPORT_PASSWORD_FILE"))
+	}()
+
+	fs := flag.NewFlagSet("app", flag.ContinueOnError)
+	report := new(Report)
+
+	c := &reported{
+		ReportTimeout: time.Minute,
+	}
+
+	err := Pick(c, WithFlagSet(fs, []string{"-report.level=debug"}), WithReport(report))
+	assert.NoError(t, err)
+
+	expectedFields := []FieldReport{
+		{Field: "ReportName", Flag: "report.name", Env: "REPORT_NAME", FileEnv: "REPORT_NAME_FILE", Source: "default", Default: true, Value: "app"},
+		{Field: "ReportPort", Flag: "report.port", Env: "REPORT_PORT", FileEnv: "REPORT_PORT_FILE", Source: "env", Value: "8080"},
+		{Field: "ReportLevel", Flag: "report.level", Env: "REPORT_LEVEL", FileEnv: "REPORT_LEVEL_FILE", Source: "flag", Value: "debug"},
+		{Field: "ReportPassword", Flag: "report.password", Env: "REPORT_PASSWORD", FileEnv: "REPORT_PASSWORD_FILE", Source: "file", File: path, Value: "*****"},
+		{Field: "ReportTimeout", Env: "REPORT_TIMEOUT", FileEnv: "REPORT_TIMEOUT_FILE", Source: "default", Default: true, Value: "1m0s"},
+	}
+
+	assert.Equal(t, expectedFields, report.Fields())
+}
+
+func TestWatchWithReport(t *testing.T) {
+	type reported struct {
+		sync.Mutex
+		WatchLevel string
+	}
+
+	path := filepath.Join(t.TempDir(), "level")
+	assert.NoError(t, os.WriteFile(path, []byte("info"), 0644))
+
+	assert.NoError(t, os.Setenv("WATCH_LEVEL_FILE", path))
+	defer func() {
+		assert.NoError(t, os.Unsetenv("WATCH_LEVEL_FILE"))
+	}()
+
+	origArgs := os.Args
+	defer func() {
+		os.Args = origArgs
+	}()
+
+	os.Args = []string{"app"}
+
+	report := new(Report)
+
+	w, err := WatchContext(context.Background(), new(reported), nil, WithReport(report))
+	assert.NoError(t, err)
+	defer w.Close()
+
+	assert.Equal(t, "info", report.Fields()[0].Value)
+
+	assert.NoError(t, os.WriteFile(path, []byte("debug"), 0644))
+
+	assert.Eventually(t, func() bool {
+		fr := report.Fields()[0]
+		return fr.Value == "debug" && fr.Source == "file" && fr.File == path
+	}, 2*time.Second, 10*time.Millisecond)
+}
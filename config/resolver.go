@@ -0,0 +1,253 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const mask = "*****"
+
+// Resolver resolves a reference to a secret (e.g. vault://kv/data/db#password) into the secret value.
+// Resolvers can be implemented for any secret manager and registered for a URI scheme using RegisterResolver.
+type Resolver interface {
+	Resolve(ref *url.URL) (string, error)
+}
+
+// ResolverFunc is an adapter for using an ordinary function as a Resolver.
+type ResolverFunc func(ref *url.URL) (string, error)
+
+// Resolve calls f(ref).
+func (f ResolverFunc) Resolve(ref *url.URL) (string, error) {
+	return f(ref)
+}
+
+// resolvers is the registry of resolvers for URI schemes.
+var resolvers = struct {
+	sync.RWMutex
+	m map[string]Resolver
+}{
+	m: map[string]Resolver{
+		"file": ResolverFunc(resolveFile),
+		"env":  ResolverFunc(resolveEnv),
+	},
+}
+
+// RegisterResolver registers a resolver for values referencing secrets with a given URI scheme (i.e. vault).
+// The file and env schemes are registered by default and can be overridden.
+// References are only resolved when the ResolveSecrets option is set.
+func RegisterResolver(scheme string, resolver Resolver) {
+	resolvers.Lock()
+	defer resolvers.Unlock()
+
+	resolvers.m[strings.ToLower(scheme)] = resolver
+}
+
+// getResolver returns the resolver registered for a value in the form of scheme://...
+// It returns false if the value is not a reference or no resolver is registered for its scheme.
+func getResolver(val string) (Resolver, *url.URL, bool) {
+	scheme, _, ok := strings.Cut(val, "://")
+	if !ok || scheme == "" {
+		return nil, nil, false
+	}
+
+	resolvers.RLock()
+	resolver, ok := resolvers.m[strings.ToLower(scheme)]
+	resolvers.RUnlock()
+
+	if !ok {
+		return nil, nil, false
+	}
+
+	ref, err := url.Parse(val)
+	if err != nil {
+		return nil, nil, false
+	}
+
+	return resolver, ref, true
+}
+
+// resolveFile reads a secret from a file.
+//
+//	file:///run/secrets/db  -->  /run/secrets/db
+//	file://secrets/db       -->  secrets/db
+func resolveFile(ref *url.URL) (string, error) {
+	path := filepath.Clean(ref.Host + ref.Path)
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+// resolveEnv reads a secret from an environment variable.
+//
+//	env://DB_PASSWORD  -->  DB_PASSWORD
+func resolveEnv(ref *url.URL) (string, error) {
+	name := ref.Host
+
+	val, ok := os.LookupEnv(name)
+	if !ok {
+		return "", fmt.Errorf("environment variable %s is not set", name)
+	}
+
+	return val, nil
+}
+
+// secretSet is the set of the names of fields holding secrets and fields with values resolved from secrets.
+// The values of both are masked in logs and the values of secret fields are masked in updates.
+type secretSet struct {
+	sync.RWMutex
+	fields   map[string]bool
+	resolved map[string]bool
+}
+
+func newSecretSet() *secretSet {
+	return &secretSet{
+		fields:   map[string]bool{},
+		resolved: map[string]bool{},
+	}
+}
+
+func (s *secretSet) addResolved(name string) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.resolved[name] = true
+}
+
+func (s *secretSet) addField(name string) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.fields[name] = true
+}
+
+// isField determines whether or not a field holds a secret.
+func (s *secretSet) isField(name string) bool {
+	if s == nil {
+		return false
+	}
+
+	s.RLock()
+	defer s.RUnlock()
+
+	return s.fields[name]
+}
+
+// isMasked determines whether or not the values of a field are masked in logs.
+func (s *secretSet) isMasked(name string) bool {
+	if s == nil {
+		return false
+	}
+
+	s.RLock()
+	defer s.RUnlock()
+
+	return s.fields[name] || s.resolved[name]
+}
+
+// maskValue returns the mask instead of a non-empty value if the value is secret.
+func maskValue(secret bool, val string) string {
+	if secret && val != "" {
+		return mask
+	}
+	return val
+}
+
+// maskError replaces the values read for a field holding a secret in an error returned for the field.
+// Errors returned when parsing values generally include the values.
+func maskError(err error, vals ...string) error {
+	msg := err.Error()
+	for _, val := range vals {
+		if val != "" {
+			msg = strings.ReplaceAll(msg, strconv.Quote(val), strconv.Quote(mask))
+			msg = strings.ReplaceAll(msg, val, mask)
+		}
+	}
+
+	return errors.New(msg)
+}
+
+// maskedValue is a value logged for a field that is masked if the field holds a secret.
+// Otherwise, it is formatted using the verb in the log message.
+type maskedValue struct {
+	masked bool
+	val    interface{}
+}
+
+func (v maskedValue) Format(s fmt.State, verb rune) {
+	if v.masked {
+		_, _ = io.WriteString(s, mask)
+		return
+	}
+
+	fmt.Fprintf(s, fmt.FormatString(s, verb), v.val)
+}
+
+// logValue returns a value for logging that is masked if the field holds a secret or its value is resolved from a secret.
+func (r *reader) logValue(name string, val interface{}) interface{} {
+	return maskedValue{
+		masked: r.secrets.isMasked(name),
+		val:    val,
+	}
+}
+
+// protectValue keeps the track of a field holding a secret, so its values are masked from then on.
+func (r *reader) protectValue(f fieldInfo, val string) {
+	if !f.secret {
+		return
+	}
+
+	if r.secrets == nil {
+		r.secrets = newSecretSet()
+	}
+
+	r.secrets.addField(f.name)
+}
+
+// isReference determines whether or not a value is a reference to be resolved.
+func (r *reader) isReference(val string) bool {
+	if !r.resolveSecrets {
+		return false
+	}
+
+	_, _, ok := getResolver(val)
+	return ok
+}
+
+// resolveValue resolves a value referencing a secret using the resolver registered for its scheme.
+// Values that are not references are returned as they are.
+// The values of fields resolved from secrets are masked in logs from then on.
+func (r *reader) resolveValue(f fieldInfo, val string) (string, error) {
+	if !r.resolveSecrets {
+		return val, nil
+	}
+
+	resolver, ref, ok := getResolver(val)
+	if !ok {
+		return val, nil
+	}
+
+	secret, err := resolver.Resolve(ref)
+	if err != nil {
+		return "", fmt.Errorf("cannot resolve %s: %s", ref.Redacted(), err)
+	}
+
+	if r.secrets == nil {
+		r.secrets = newSecretSet()
+	}
+	r.secrets.addResolved(f.name)
+
+	r.log(5, "[%s] value resolved from %s: %s", f.name, ref.Redacted(), mask)
+
+	return secret, nil
+}
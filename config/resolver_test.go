@@ -0,0 +1,275 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// restoreResolvers restores the registry of resolvers once a test is finished.
+func restoreResolvers(t *testing.T) {
+	resolvers.RLock()
+	m := maps.Clone(resolvers.m)
+	resolvers.RUnlock()
+
+	t.Cleanup(func() {
+		resolvers.Lock()
+		resolvers.m = m
+		resolvers.Unlock()
+	})
+}
+
+// registerVault registers a stand-in for a secret manager until a test is finished.
+func registerVault(t *testing.T) {
+	restoreResolvers(t)
+
+	RegisterResolver("vault", ResolverFunc(func(ref *url.URL) (string, error) {
+		if ref.Host+ref.Path == "kv/data/db" && ref.Fragment == "password" {
+			return "s3cr3t", nil
+		}
+		return "", errors.New("secret not found")
+	}))
+}
+
+func TestRegisterResolver(t *testing.T) {
+	restoreResolvers(t)
+
+	_, _, ok := getResolver("custom://secret")
+	assert.False(t, ok)
+
+	RegisterResolver("Custom", ResolverFunc(func(*url.URL) (string, error) {
+		return "value", nil
+	}))
+
+	resolver, ref, ok := getResolver("custom://secret")
+	assert.True(t, ok)
+	assert.Equal(t, "secret", ref.Host)
+
+	val, err := resolver.Resolve(ref)
+	assert.NoError(t, err)
+	assert.Equal(t, "value", val)
+}
+
+func TestGetResolver(t *testing.T) {
+	registerVault(t)
+
+	tests := []struct {
+		name       string
+		val        string
+		expectedOK bool
+	}{
+		{"PlainValue", "password", false},
+		{"HostPort", "localhost:8080", false},
+		{"UnknownScheme", "https://example.com", false},
+		{"NoScheme", "://value", false},
+		{"InvalidURL", "file://%zz", false},
+		{"File", "file:///run/secrets/db", true},
+		{"Env", "env://DB_PASSWORD", true},
+		{"Vault", "vault://kv/data/db#password", true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, ok := getResolver(tc.val)
+			assert.Equal(t, tc.expectedOK, ok)
+		})
+	}
+}
+
+func TestBuiltinResolvers(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "db")
+	assert.NoError(t, os.WriteFile(path, []byte("from-file"), 0644))
+
+	assert.NoError(t, os.Setenv("RESOLVER_PASSWORD", "from-env"))
+	defer func() {
+		assert.NoError(t, os.Unsetenv("RESOLVER_PASSWORD"))
+	}()
+
+	tests := []struct {
+		name          string
+		val           string
+		expectedValue string
+		expectedError bool
+	}{
+		{"File", "file://" + path, "from-file", false},
+		{"FileMissing", "file://" + filepath.Join(dir, "missing"), "", true},
+		{"Env", "env://RESOLVER_PASSWORD", "from-env", false},
+		{"EnvMissing", "env://RESOLVER_MISSING", "", true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			resolver, ref, ok := getResolver(tc.val)
+			assert.True(t, ok)
+
+			val, err := resolver.Resolve(ref)
+
+			if tc.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedValue, val)
+			}
+		})
+	}
+}
+
+func TestSecretSet(t *testing.T) {
+	var nilSet *secretSet
+	assert.False(t, nilSet.isField("Password"))
+	assert.False(t, nilSet.isMasked("Password"))
+
+	s := newSecretSet()
+	s.addField("Password")
+	s.addResolved("DatabaseURL")
+
+	assert.True(t, s.isField("Password"))
+	assert.True(t, s.isMasked("Password"))
+	assert.False(t, s.isField("DatabaseURL"))
+	assert.True(t, s.isMasked("DatabaseURL"))
+	assert.False(t, s.isMasked("Port"))
+}
+
+func TestMaskError(t *testing.T) {
+	err := errors.New(`strconv.ParseInt: parsing "s3cr3t": invalid syntax`)
+	assert.EqualError(t, maskError(err, "", "s3cr3t"), `strconv.ParseInt: parsing "*****": invalid syntax`)
+}
+
+func TestReaderLogValue(t *testing.T) {
+	r := &reader{secrets: newSecretSet()}
+	r.secrets.addField("Password")
+
+	// Values of other fields are never masked, even if they contain a secret value
+	assert.Equal(t, "setting 8080 and 1.50", fmt.Sprintf("setting %d and %.2f", r.logValue("Port", 8080), r.logValue("Ratio", 1.5)))
+	assert.Equal(t, "setting *****", fmt.Sprintf("setting %d", r.logValue("Password", 8080)))
+}
+
+func TestReaderResolveValue(t *testing.T) {
+	registerVault(t)
+
+	f := fieldInfo{name: "Password"}
+
+	tests := []struct {
+		name          string
+		r             *reader
+		val           string
+		expectedValue string
+		expectedError string
+	}{
+		{"Disabled", &reader{}, "vault://kv/data/db#password", "vault://kv/data/db#password", ""},
+		{"PlainValue", &reader{resolveSecrets: true}, "password", "password", ""},
+		{"Resolved", &reader{resolveSecrets: true}, "vault://kv/data/db#password", "s3cr3t", ""},
+		{"NotFound", &reader{resolveSecrets: true}, "vault://user:token@kv/data/db#username", "", "cannot resolve vault://user:xxxxx@kv/data/db#username: secret not found"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			val, err := tc.r.resolveValue(f, tc.val)
+
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedValue, val)
+			} else {
+				assert.EqualError(t, err, tc.expectedError)
+			}
+		})
+	}
+}
+
+func TestReadFieldsWithSecrets(t *testing.T) {
+	registerVault(t)
+
+	type secrets struct {
+		Password string
+		Port     int
+	}
+
+	assert.NoError(t, os.Setenv("PASSWORD", "vault://kv/data/db#password"))
+	assert.NoError(t, os.Setenv("PORT", "vault://kv/data/db#password"))
+	defer func() {
+		assert.NoError(t, os.Unsetenv("PASSWORD"))
+		assert.NoError(t, os.Unsetenv("PORT"))
+	}()
+
+	origArgs := os.Args
+	defer func() {
+		os.Args = origArgs
+	}()
+
+	os.Args = []string{"app"}
+
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	r := &reader{
+		debug:          5,
+		resolveSecrets: true,
+		filesToFields:  map[string]fieldInfo{},
+	}
+
+	s := new(secrets)
+	err := r.readFields(reflect.ValueOf(s).Elem())
+
+	assert.Equal(t, "s3cr3t", s.Password)
+	assert.Equal(t, 0, s.Port)
+
+	// The reference should be reported instead of the secret
+	var ferr *FieldError
+	assert.True(t, errors.As(err, &ferr))
+	assert.Equal(t, "Port", ferr.Field)
+	assert.Equal(t, "vault://kv/data/db#password", ferr.Value)
+	assert.NotContains(t, err.Error(), "s3cr3t")
+
+	// The secret should be masked in logs
+	assert.Contains(t, buf.String(), "[Password] value resolved from vault://kv/data/db#password: *****")
+	assert.NotContains(t, buf.String(), "s3cr3t")
+}
+
+func TestPickMasksSecretsInLogs(t *testing.T) {
+	type logged struct {
+		MaskedPassword          string
+		MaskedPasswordMinLength int
+		MaskedPort              int
+	}
+
+	env := map[string]string{
+		"MASKED_PASSWORD":            "8080",
+		"MASKED_PASSWORD_MIN_LENGTH": "8",
+		"MASKED_PORT":                "8080",
+	}
+
+	for name, val := range env {
+		assert.NoError(t, os.Setenv(name, val))
+	}
+
+	defer func() {
+		for name := range env {
+			assert.NoError(t, os.Unsetenv(name))
+		}
+	}()
+
+	buf := new(bytes.Buffer)
+	log.SetOutput(buf)
+	defer log.SetOutput(os.Stderr)
+
+	c := new(logged)
+	err := Pick(c, SkipFlag(), Debug(5))
+	assert.NoError(t, err)
+	assert.Equal(t, &logged{"8080", 8, 8080}, c)
+
+	// Only the values of the secret field are masked
+	assert.Contains(t, buf.String(), "[MaskedPassword] setting string value: *****")
+	assert.Contains(t, buf.String(), "[MaskedPasswordMinLength] setting int value: 8\n")
+	assert.Contains(t, buf.String(), "[MaskedPort] setting int value: 8080\n")
+}
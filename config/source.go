@@ -0,0 +1,194 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+)
+
+// Key identifies a field for looking up its value from a source.
+// Names that are skipped for a field are empty.
+type Key struct {
+	// Field is the name of the field (i.e. Log.Level).
+	Field string
+	// Flag is the command-line flag name for the field (i.e. log.level).
+	Flag string
+	// Env is the environment variable name for the field (i.e. LOG_LEVEL).
+	Env string
+	// FileEnv is the name of the environment variable holding a file path for the field (i.e. LOG_LEVEL_FILE).
+	FileEnv string
+	// Path is the path to the field in nested documents (i.e. [log level]).
+	Path []string
+
+	listSep string
+	layout  string
+	secret  bool
+}
+
+// Source is a source of configuration values.
+type Source interface {
+	// Name returns a short name for the source used in logs and errors.
+	Name() string
+	// Lookup returns the value of a field in the source.
+	// The second returned value is false if the source has no value for the field.
+	// Lookup may be called concurrently with Watch.
+	Lookup(key Key) (string, bool)
+}
+
+// WatchableSource is a source that can notify about changes to its values.
+type WatchableSource interface {
+	Source
+	// Watch calls notify every time the values of the source are changed until the context is cancelled.
+	// If the values cannot be read (i.e. a remote server is not available), the error is passed to report.
+	Watch(ctx context.Context, notify func(), report func(error))
+}
+
+// getKey returns the key for looking up the value of a field from sources.
+func (f fieldInfo) getKey() Key {
+	key := Key{
+		Field:   f.name,
+		listSep: f.listSep,
+		layout:  f.layout,
+		secret:  f.secret,
+	}
+
+	if f.flagName != skip {
+		key.Flag = f.flagName
+	}
+
+	if f.envName != skip {
+		key.Env = f.envName
+	}
+
+	if f.fileEnvName != skip {
+		key.FileEnv = f.fileEnvName
+	}
+
+	if len(f.docKeys) > 0 && f.docKeys[0] != skip {
+		key.Path = f.docKeys
+	}
+
+	return key
+}
+
+// getSources returns the sources for reading values in the order of their priorities.
+// The built-in sources (command-line flags, environment variables, and configuration files) come first.
+func (r *reader) getSources() []Source {
+	sources := make([]Source, 0, 3+len(r.sources))
+
+	if !r.skipFlag {
+		sources = append(sources, &flagSource{r})
+	}
+
+	if !r.skipEnv {
+		sources = append(sources, &envSource{r})
+	}
+
+	if !r.skipFileEnv {
+		sources = append(sources, &fileSource{r})
+	}
+
+	return append(sources, r.sources...)
+}
+
+// flagSource reads values from command-line flags.
+type flagSource struct {
+	r *reader
+}
+
+func (s *flagSource) Name() string {
+	return sourceFlag
+}
+
+func (s *flagSource) Lookup(key Key) (string, bool) {
+	if key.Flag == "" {
+		return "", false
+	}
+
+	var value string
+	if s.r.flagSet != nil {
+		value = s.r.flagValues[key.Flag]
+	} else {
+		value = getFlagValue(key.Flag)
+	}
+
+	s.r.log(5, "[%s] value read from flag %s: %s", key.Field, key.Flag, maskValue(key.secret, value))
+
+	return value, value != ""
+}
+
+// envSource reads values from environment variables.
+type envSource struct {
+	r *reader
+}
+
+func (s *envSource) Name() string {
+	return sourceEnv
+}
+
+func (s *envSource) Lookup(key Key) (string, bool) {
+	if key.Env == "" {
+		return "", false
+	}
+
+	value, name := s.r.lookupProfileEnv(key.Env)
+	s.r.log(5, "[%s] value read from environment variable %s: %s", key.Field, name, maskValue(key.secret, value))
+
+	return value, value != ""
+}
+
+// fileSource reads values from configuration files specified by environment variables.
+type fileSource struct {
+	r *reader
+}
+
+func (s *fileSource) Name() string {
+	return sourceFile
+}
+
+func (s *fileSource) Lookup(key Key) (string, bool) {
+	if key.FileEnv == "" {
+		return "", false
+	}
+
+	filePath := s.path(key)
+	s.r.log(5, "[%s] value read from file environment variable %s: %s", key.Field, key.FileEnv, filePath)
+
+	if filePath == "" {
+		return "", false
+	}
+
+	b, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", false
+	}
+
+	value := string(b)
+	s.r.log(5, "[%s] value read from %s: %s", key.Field, filePath, maskValue(key.secret, value))
+
+	return value, value != ""
+}
+
+// path returns the path to the configuration file for a field.
+// If the Telepresence option is set, the path is joined with the mount path of volumes.
+func (s *fileSource) path(key Key) string {
+	if key.FileEnv == "" {
+		return ""
+	}
+
+	// Read file environment variable
+	filePath, _ := s.r.lookupProfileEnv(key.FileEnv)
+	if filePath == "" {
+		return ""
+	}
+
+	// Check for Telepresence
+	// See https://telepresence.io/howto/volumes.html for details
+	if s.r.telepresence {
+		if mountPath := s.r.getEnv(envTelepresenceRoot); mountPath != "" {
+			filePath = filepath.Join(mountPath, filePath)
+		}
+	}
+
+	return filepath.Clean(filePath)
+}
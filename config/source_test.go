@@ -0,0 +1,227 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// mapSource is a source reading values from a map of dot-separated paths to values.
+type mapSource map[string]string
+
+func (s mapSource) Name() string {
+	return "map"
+}
+
+func (s mapSource) Lookup(key Key) (string, bool) {
+	val, ok := s[strings.Join(key.Path, ".")]
+	return val, ok
+}
+
+func TestFieldInfoGetKey(t *testing.T) {
+	tests := []struct {
+		name        string
+		f           fieldInfo
+		expectedKey Key
+	}{
+		{
+			"AllNames",
+			fieldInfo{
+				name:        "Log.Level",
+				flagName:    "log.level",
+				envName:     "LOG_LEVEL",
+				fileEnvName: "LOG_LEVEL_FILE",
+				docKeys:     []string{"log", "level"},
+				listSep:     ",",
+				layout:      "2006-01-02",
+			},
+			Key{
+				Field:   "Log.Level",
+				Flag:    "log.level",
+				Env:     "LOG_LEVEL",
+				FileEnv: "LOG_LEVEL_FILE",
+				Path:    []string{"log", "level"},
+				listSep: ",",
+				layout:  "2006-01-02",
+			},
+		},
+		{
+			"SkippedNames",
+			fieldInfo{
+				name:        "Log.Level",
+				flagName:    "-",
+				envName:     "-",
+				fileEnvName: "-",
+				docKeys:     []string{"-"},
+			},
+			Key{
+				Field: "Log.Level",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedKey, tc.f.getKey())
+		})
+	}
+}
+
+func TestReaderGetSources(t *testing.T) {
+	src := mapSource{}
+
+	tests := []struct {
+		name          string
+		r             *reader
+		expectedNames []string
+	}{
+		{"Default", &reader{}, []string{"flag", "env", "file"}},
+		{"SkipAll", &reader{skipFlag: true, skipEnv: true, skipFileEnv: true}, []string{}},
+		{"WithSources", &reader{skipEnv: true, sources: []Source{src}}, []string{"flag", "file", "map"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			names := []string{}
+			for _, s := range tc.r.getSources() {
+				names = append(names, s.Name())
+			}
+
+			assert.Equal(t, tc.expectedNames, names)
+		})
+	}
+}
+
+func TestBuiltinSources(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "level")
+	assert.NoError(t, os.WriteFile(path, []byte("warn"), 0644))
+
+	assert.NoError(t, os.Setenv("SOURCE_LEVEL", "info"))
+	assert.NoError(t, os.Setenv("SOURCE_LEVEL_FILE", path))
+	assert.NoError(t, os.Setenv("SOURCE_MISSING_FILE", "/missing"))
+
+	defer func() {
+		assert.NoError(t, os.Unsetenv("SOURCE_LEVEL"))
+		assert.NoError(t, os.Unsetenv("SOURCE_LEVEL_FILE"))
+		assert.NoError(t, os.Unsetenv("SOURCE_MISSING_FILE"))
+	}()
+
+	origArgs := os.Args
+	defer func() {
+		os.Args = origArgs
+	}()
+
+	os.Args = []string{"app", "-source.level=debug"}
+
+	r := &reader{}
+
+	tests := []struct {
+		name          string
+		src           Source
+		key           Key
+		expectedValue string
+		expectedOK    bool
+	}{
+		{"Flag", &flagSource{r}, Key{Flag: "source.level"}, "debug", true},
+		{"FlagMissing", &flagSource{r}, Key{Flag: "source.missing"}, "", false},
+		{"FlagSkipped", &flagSource{r}, Key{}, "", false},
+		{"Env", &envSource{r}, Key{Env: "SOURCE_LEVEL"}, "info", true},
+		{"EnvMissing", &envSource{r}, Key{Env: "SOURCE_MISSING"}, "", false},
+		{"EnvSkipped", &envSource{r}, Key{}, "", false},
+		{"File", &fileSource{r}, Key{FileEnv: "SOURCE_LEVEL_FILE"}, "warn", true},
+		{"FileMissing", &fileSource{r}, Key{FileEnv: "SOURCE_MISSING_FILE"}, "", false},
+		{"FileSkipped", &fileSource{r}, Key{}, "", false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			value, ok := tc.src.Lookup(tc.key)
+
+			assert.Equal(t, tc.expectedValue, value)
+			assert.Equal(t, tc.expectedOK, ok)
+		})
+	}
+}
+
+func TestGetFieldValueWithSources(t *testing.T) {
+	assert.NoError(t, os.Setenv("SOURCE_PORT", "8080"))
+	defer func() {
+		assert.NoError(t, os.Unsetenv("SOURCE_PORT"))
+	}()
+
+	origArgs := os.Args
+	defer func() {
+		os.Args = origArgs
+	}()
+
+	os.Args = []string{"app"}
+
+	var port int
+	var level string
+
+	r := &reader{
+		sources: []Source{
+			mapSource{"source.port": "9090"},
+			mapSource{"source.port": "7070", "source.level": "debug"},
+		},
+		doc: &document{
+			data: map[string]interface{}{
+				"source": map[string]interface{}{
+					"level": "info",
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name             string
+		f                fieldInfo
+		expectedValue    string
+		expectedSource   string
+		expectedFilePath string
+	}{
+		{
+			"EnvOverSources",
+			fieldInfo{
+				value:   reflect.ValueOf(&port).Elem(),
+				name:    "Source.Port",
+				envName: "SOURCE_PORT",
+				docKeys: []string{"source", "port"},
+			},
+			"8080", "env", "",
+		},
+		{
+			"FirstSource",
+			fieldInfo{
+				value:   reflect.ValueOf(&port).Elem(),
+				name:    "Source.Port",
+				envName: "-",
+				docKeys: []string{"source", "port"},
+			},
+			"9090", "map", "",
+		},
+		{
+			"SourceOverDocument",
+			fieldInfo{
+				value:   reflect.ValueOf(&level).Elem(),
+				name:    "Source.Level",
+				docKeys: []string{"source", "level"},
+			},
+			"debug", "map", "",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			value, source, filePath := r.getFieldValue(tc.f)
+
+			assert.Equal(t, tc.expectedValue, value)
+			assert.Equal(t, tc.expectedSource, source)
+			assert.Equal(t, tc.expectedFilePath, filePath)
+		})
+	}
+}
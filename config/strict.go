@@ -0,0 +1,174 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	multierror "github.com/hashicorp/go-multierror"
+)
+
+// UnknownError is the error for an environment variable or a flag that matches no field (see Strict option).
+type UnknownError struct {
+	// Source is where the name is found (flag or env).
+	Source string
+	// Name is the name of the environment variable or the flag.
+	Name string
+	// Suggestion is the closest known name if any.
+	Suggestion string
+	// Field is the name of the field for the suggested name.
+	Field string
+}
+
+func (e *UnknownError) Error() string {
+	name, suggestion := e.Name, e.Suggestion
+	if e.Source == sourceFlag {
+		name, suggestion = "-"+name, "-"+suggestion
+	}
+
+	if e.Suggestion == "" {
+		return fmt.Sprintf("unknown %s %s", e.Source, name)
+	}
+
+	return fmt.Sprintf("unknown %s %s (did you mean %s for %s?)", e.Source, name, suggestion, e.Field)
+}
+
+// editDistance returns the Levenshtein distance between two strings.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(b)]
+}
+
+// suggest returns the closest known name to an unknown name.
+// Names that are too different from the unknown name are not suggested.
+func suggest(name string, known map[string]string) (string, string) {
+	names := make([]string, 0, len(known))
+	for n := range known {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	var best string
+	bestDist := max(2, len(name)/4) + 1
+
+	for _, n := range names {
+		if d := editDistance(name, n); d < bestDist {
+			best, bestDist = n, d
+		}
+	}
+
+	if best == "" {
+		return "", ""
+	}
+
+	return best, known[best]
+}
+
+// checkUnknown reports the environment variables with the active prefixes (see PrefixEnv and PrefixFileEnv options)
+// and the flags passed on the command line that match no field of a struct.
+// Environment variables are only checked if a prefix is set, since the environment has many variables unrelated to the application.
+func (r *reader) checkUnknown(v reflect.Value) error {
+	if !r.strict {
+		return nil
+	}
+
+	r.log(2, "Checking unknown environment variables and flags ...")
+
+	envNames := map[string]string{}
+	flagNames := map[string]string{}
+	aliases := map[string]bool{}
+
+	r.iterateOnFields(v, func(f fieldInfo) {
+		if f.envName != skip {
+			envNames[f.envName] = f.name
+		}
+		if f.fileEnvName != skip {
+			envNames[f.fileEnvName] = f.name
+		}
+		if f.flagName != skip {
+			flagNames[f.flagName] = f.name
+		}
+
+		// Aliases are known names too, but they are never suggested
+		for _, alias := range f.aliases {
+			aliases[alias] = true
+			aliases[alias+"_FILE"] = true
+			aliases[getAliasFlagName(alias)] = true
+		}
+	})
+
+	var errs error
+
+	prefixes := []string{}
+	if !r.skipEnv && r.prefixEnv != "" {
+		prefixes = append(prefixes, r.prefixEnv)
+	}
+	if !r.skipFileEnv && r.prefixFileEnv != "" {
+		prefixes = append(prefixes, r.prefixFileEnv)
+	}
+
+	seenEnv := map[string]bool{}
+	for _, name := range r.envNames() {
+		if seenEnv[name] || envNames[name] != "" || aliases[name] {
+			continue
+		}
+		seenEnv[name] = true
+
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(name, prefix) {
+				err := &UnknownError{Source: sourceEnv, Name: name}
+				err.Suggestion, err.Field = suggest(name, envNames)
+				r.log(1, err.Error())
+				errs = multierror.Append(errs, err)
+				break
+			}
+		}
+	}
+
+	if !r.skipFlag {
+		seenFlag := map[string]bool{}
+		for _, name := range r.flagNames() {
+			if name == "" || seenFlag[name] || flagNames[name] != "" || aliases[name] || r.isFlagDefined(name) {
+				continue
+			}
+			seenFlag[name] = true
+
+			err := &UnknownError{Source: sourceFlag, Name: name}
+			err.Suggestion, err.Field = suggest(name, flagNames)
+			r.log(1, err.Error())
+			errs = multierror.Append(errs, err)
+		}
+	}
+
+	return errs
+}
+
+// isFlagDefined determines whether or not a flag is defined by the application or the reader itself.
+func (r *reader) isFlagDefined(name string) bool {
+	if r.flagSet != nil {
+		return r.flagSet.Lookup(name) != nil
+	}
+
+	// Help flags are handled even if not defined when the Help option is set (see checkHelp)
+	return flag.Lookup(name) != nil || (r.help && (name == flagHelp || name == flagHelpShort))
+}
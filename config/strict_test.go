@@ -0,0 +1,160 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnknownError(t *testing.T) {
+	tests := []struct {
+		name          string
+		err           *UnknownError
+		expectedError string
+	}{
+		{
+			name:          "Env",
+			err:           &UnknownError{Source: sourceEnv, Name: "APP_DATABSE_URL"},
+			expectedError: "unknown env APP_DATABSE_URL",
+		},
+		{
+			name:          "EnvWithSuggestion",
+			err:           &UnknownError{Source: sourceEnv, Name: "APP_DATABSE_URL", Suggestion: "APP_DATABASE_URL", Field: "DatabaseURL"},
+			expectedError: "unknown env APP_DATABSE_URL (did you mean APP_DATABASE_URL for DatabaseURL?)",
+		},
+		{
+			name:          "FlagWithSuggestion",
+			err:           &UnknownError{Source: sourceFlag, Name: "databse.url", Suggestion: "database.url", Field: "DatabaseURL"},
+			expectedError: "unknown flag -databse.url (did you mean -database.url for DatabaseURL?)",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.EqualError(t, tc.err, tc.expectedError)
+		})
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b             string
+		expectedDistance int
+	}{
+		{"", "", 0},
+		{"", "abc", 3},
+		{"abc", "abc", 0},
+		{"DATABSE_URL", "DATABASE_URL", 1},
+		{"PORT", "PROT", 2},
+		{"kitten", "sitting", 3},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.a+"_"+tc.b, func(t *testing.T) {
+			assert.Equal(t, tc.expectedDistance, editDistance(tc.a, tc.b))
+			assert.Equal(t, tc.expectedDistance, editDistance(tc.b, tc.a))
+		})
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	known := map[string]string{
+		"DATABASE_URL":  "DatabaseURL",
+		"DATABASE_USER": "DatabaseUser",
+		"LOG_LEVEL":     "LogLevel",
+	}
+
+	tests := []struct {
+		name               string
+		unknown            string
+		expectedSuggestion string
+		expectedField      string
+	}{
+		{"Typo", "DATABSE_URL", "DATABASE_URL", "DatabaseURL"},
+		{"Closest", "DATABASE_USR", "DATABASE_USER", "DatabaseUser"},
+		{"TooDifferent", "HTTP_PORT", "", ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			suggestion, field := suggest(tc.unknown, known)
+
+			assert.Equal(t, tc.expectedSuggestion, suggestion)
+			assert.Equal(t, tc.expectedField, field)
+		})
+	}
+}
+
+func TestPickStrict(t *testing.T) {
+	type strictConfig struct {
+		StrictDatabaseURL string
+		StrictLogLevel    string
+		StrictOffset      int
+	}
+
+	origArgs := os.Args
+	defer func() {
+		os.Args = origArgs
+	}()
+
+	env := map[string]string{
+		"APP_STRICT_DATABSE_URL": "postgres://localhost",
+		"APP_STRICT_LOG_LEVEL":   "info",
+		"APP_UNRELATED":          "value",
+	}
+
+	for name, val := range env {
+		assert.NoError(t, os.Setenv(name, val))
+	}
+
+	defer func() {
+		for name := range env {
+			assert.NoError(t, os.Unsetenv(name))
+		}
+	}()
+
+	t.Run("NotStrict", func(t *testing.T) {
+		os.Args = []string{"app", "-strict.log.levle=debug"}
+
+		c := new(strictConfig)
+		err := Pick(c, PrefixEnv("APP_"))
+		assert.NoError(t, err)
+		assert.Equal(t, &strictConfig{StrictLogLevel: "info"}, c)
+	})
+
+	t.Run("Strict", func(t *testing.T) {
+		os.Args = []string{"app", "-strict.log.levle=debug", "-strict.database.url", "postgres://remote"}
+
+		c := new(strictConfig)
+		err := Pick(c, PrefixEnv("APP_"), Strict())
+		assert.Error(t, err)
+
+		var uerr *UnknownError
+		assert.True(t, errors.As(err, &uerr))
+
+		assert.Contains(t, err.Error(), "unknown env APP_STRICT_DATABSE_URL (did you mean APP_STRICT_DATABASE_URL for StrictDatabaseURL?)")
+		assert.Contains(t, err.Error(), "unknown env APP_UNRELATED")
+		assert.Contains(t, err.Error(), "unknown flag -strict.log.levle (did you mean -strict.log.level for StrictLogLevel?)")
+		assert.NotContains(t, err.Error(), "APP_STRICT_LOG_LEVEL")
+		assert.NotContains(t, err.Error(), "-strict.database.url ")
+	})
+
+	t.Run("NegativeValue", func(t *testing.T) {
+		os.Args = []string{"app", "--strict.offset", "-5"}
+
+		c := new(strictConfig)
+		err := Pick(c, Strict())
+		assert.NoError(t, err)
+		assert.Equal(t, -5, c.StrictOffset)
+	})
+
+	t.Run("NoPrefix", func(t *testing.T) {
+		os.Args = []string{"app"}
+
+		c := new(strictConfig)
+		err := Pick(c, Strict())
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,212 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const (
+	flagHelp        = "help"
+	flagHelpShort   = "h"
+	flagPrintConfig = "print-config"
+)
+
+// ErrHelp is the error returned by Pick and Watch when the -help (or -h) flag is passed and the Help option is set.
+// It is the same as flag.ErrHelp, so it is also returned when a flag set is used (see WithFlagSet option).
+var ErrHelp = flag.ErrHelp
+
+// exit is used for exiting after printing the configuration (overridden in tests).
+var exit = os.Exit
+
+// Usage returns the usage of all fields of a config struct as a table grouped by nested structs.
+// The table lists the flag name, environment variable names, type, default value, and description (see the desc tag) of every field.
+// The same options passed to Pick or Watch should be passed to Usage too.
+//
+// If the Help option is set, the usage is printed when reading values and the -help (or -h) flag is passed.
+func Usage(config interface{}, opts ...Option) (string, error) {
+	c := readerFromEnv()
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	v, err := validateStruct(config)
+	if err != nil {
+		return "", err
+	}
+
+	return c.usage(v), nil
+}
+
+// usage renders the usage of all fields of a struct.
+func (r *reader) usage(v reflect.Value) string {
+	name := filepath.Base(os.Args[0])
+	if r.flagSet != nil {
+		name = r.flagSet.Name()
+	}
+
+	header := []string{"FLAG", "ENV", "FILE ENV", "TYPE", "DEFAULT", "DESCRIPTION"}
+
+	// Fields are grouped by their parent structs in the order they appear
+	groups := []string{""}
+	rows := map[string][][]string{}
+
+	r.iterateOnCopy(v, func(f fieldInfo) {
+		var group string
+		if i := strings.LastIndex(f.name, "."); i > 0 {
+			group = f.name[:i]
+		}
+
+		if _, ok := rows[group]; !ok && group != "" {
+			groups = append(groups, group)
+		}
+
+		name := func(s string) string {
+			if s == skip {
+				return ""
+			}
+			return s
+		}
+
+		flagName := name(f.flagName)
+		if flagName != "" {
+			flagName = "-" + flagName
+		}
+
+		rows[group] = append(rows[group], []string{
+			flagName,
+			name(f.envName),
+			name(f.fileEnvName),
+			f.value.Type().String(),
+			maskValue(f.secret, getDefaultValue(f)),
+			f.desc,
+		})
+	})
+
+	// Columns are aligned across all groups
+	widths := make([]int, len(header))
+	for _, group := range groups {
+		for _, row := range append([][]string{header}, rows[group]...) {
+			for i, cell := range row {
+				if len(cell) > widths[i] {
+					widths[i] = len(cell)
+				}
+			}
+		}
+	}
+
+	var b strings.Builder
+
+	writeRow := func(row []string) {
+		line := " "
+		for i, cell := range row {
+			line += fmt.Sprintf(" %-*s ", widths[i], cell)
+		}
+		b.WriteString(strings.TrimRight(line, " ") + "\n")
+	}
+
+	fmt.Fprintf(&b, "Usage of %s:\n\n", name)
+	writeRow(header)
+
+	for _, group := range groups {
+		if len(rows[group]) == 0 {
+			continue
+		}
+
+		if group != "" {
+			fmt.Fprintf(&b, "\n%s:\n", group)
+		}
+
+		for _, row := range rows[group] {
+			writeRow(row)
+		}
+	}
+
+	return b.String()
+}
+
+// isFlagSet determines whether or not a boolean flag is passed on the command line.
+func (r *reader) isFlagSet(name string) bool {
+	var val string
+	if r.flagSet != nil {
+		val = r.flagValues[name]
+	} else {
+		val = getFlagValue(name)
+	}
+
+	b, _ := strconv.ParseBool(val)
+	return b
+}
+
+// registerHelp replaces the usage function of the flag set if the Help option is set,
+// so the flag set prints the usage when the -help (or -h) flag is passed.
+// Without a flag set, the -help flag is checked after parsing the flags (see checkHelp).
+func (r *reader) registerHelp(v reflect.Value) {
+	if r.help && r.flagSet != nil {
+		r.flagSet.Usage = func() {
+			fmt.Fprint(r.flagSet.Output(), r.usage(v))
+		}
+	}
+}
+
+// checkHelp prints the usage and returns ErrHelp if the Help option is set and the -help (or -h) flag is passed on the command line.
+// Help flags defined by the application are left to the application.
+func (r *reader) checkHelp(v reflect.Value) error {
+	if !r.help || r.skipFlag || r.flagSet != nil {
+		return nil
+	}
+
+	for _, name := range []string{flagHelp, flagHelpShort} {
+		if flag.Lookup(name) == nil && getFlagValue(name) != "" {
+			fmt.Fprint(flag.CommandLine.Output(), r.usage(v))
+			return ErrHelp
+		}
+	}
+
+	return nil
+}
+
+// registerPrintConfig defines the -print-config flag if the PrintConfig option is set.
+func (r *reader) registerPrintConfig() {
+	if !r.printConfig {
+		return
+	}
+
+	usage := "Print the effective configuration and exit"
+
+	if r.flagSet != nil {
+		if r.flagSet.Lookup(flagPrintConfig) == nil {
+			r.flagSet.Bool(flagPrintConfig, false, usage)
+		}
+		return
+	}
+
+	if flag.Lookup(flagPrintConfig) == nil {
+		flag.Bool(flagPrintConfig, false, usage)
+	}
+}
+
+// checkPrintConfig prints the effective configuration and exits if the -print-config flag is passed on the command line.
+// Values of fields holding secrets are redacted (see Dump).
+func (r *reader) checkPrintConfig(v reflect.Value) {
+	if !r.printConfig || !r.isFlagSet(flagPrintConfig) {
+		return
+	}
+
+	// Values are only read again for determining their sources
+	r.debug = 0
+
+	out, err := r.dump(v)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		exit(1)
+		return
+	}
+
+	fmt.Fprint(os.Stdout, out)
+	exit(0)
+}
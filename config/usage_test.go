@@ -0,0 +1,208 @@
+package config
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type usageConfig struct {
+	Name     string `default:"app" desc:"Name of the service"`
+	Port     int    `default:"8080"`
+	Password string `default:"s3cr3t"`
+	Internal string `flag:"-" env:"-"`
+	Log      struct {
+		Level string `default:"info" desc:"Log level"`
+	}
+}
+
+const expectedUsage = "" +
+	"Usage of app:\n" +
+	"\n" +
+	"  FLAG        ENV        FILE ENV        TYPE    DEFAULT  DESCRIPTION\n" +
+	"  -name       NAME       NAME_FILE       string  app      Name of the service\n" +
+	"  -port       PORT       PORT_FILE       int     8080\n" +
+	"  -password   PASSWORD   PASSWORD_FILE   string  *****\n" +
+	"                         INTERNAL_FILE   string\n" +
+	"\n" +
+	"Log:\n" +
+	"  -log.level  LOG_LEVEL  LOG_LEVEL_FILE  string  info     Log level\n"
+
+func TestUsage(t *testing.T) {
+	t.Run("InvalidConfig", func(t *testing.T) {
+		out, err := Usage(new(int))
+		assert.EqualError(t, err, "a non-struct type is passed")
+		assert.Empty(t, out)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		fs := flag.NewFlagSet("app", flag.ContinueOnError)
+		out, err := Usage(new(usageConfig), WithFlagSet(fs, nil))
+		assert.NoError(t, err)
+		assert.Equal(t, expectedUsage, out)
+	})
+}
+
+func TestPickWithHelp(t *testing.T) {
+	t.Run("NoHelpOption", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		fs := flag.NewFlagSet("app", flag.ContinueOnError)
+		fs.SetOutput(buf)
+
+		// The usage function of the flag set is left untouched
+		err := Pick(new(usageConfig), WithFlagSet(fs, []string{"-help"}))
+		assert.Equal(t, ErrHelp, err)
+		assert.Contains(t, buf.String(), "Usage of app:\n  -log.level value\n")
+	})
+
+	t.Run("HelpOption", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		fs := flag.NewFlagSet("app", flag.ContinueOnError)
+		fs.SetOutput(buf)
+
+		err := Pick(new(usageConfig), WithFlagSet(fs, []string{"-help"}), Help())
+		assert.Equal(t, ErrHelp, err)
+		assert.Equal(t, expectedUsage, buf.String())
+	})
+}
+
+func TestReaderCheckHelp(t *testing.T) {
+	origArgs := os.Args
+	defer func() {
+		os.Args = origArgs
+	}()
+
+	buf := new(bytes.Buffer)
+	flag.CommandLine.SetOutput(buf)
+	defer flag.CommandLine.SetOutput(nil)
+
+	tests := []struct {
+		name          string
+		r             *reader
+		args          []string
+		expectedError error
+	}{
+		{"NoHelpOption", &reader{}, []string{"app", "-help"}, nil},
+		{"NoHelp", &reader{help: true}, []string{"app"}, nil},
+		{"SkipFlag", &reader{help: true, skipFlag: true}, []string{"app", "-help"}, nil},
+		{"FlagSet", &reader{help: true, flagSet: flag.NewFlagSet("app", flag.ContinueOnError)}, []string{"app", "-help"}, nil},
+		{"Help", &reader{help: true}, []string{"app", "-help"}, ErrHelp},
+		{"ShortHelp", &reader{help: true}, []string{"app", "--h"}, ErrHelp},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			buf.Reset()
+			os.Args = tc.args
+
+			v, _ := validateStruct(new(usageConfig))
+			err := tc.r.checkHelp(v)
+
+			assert.Equal(t, tc.expectedError, err)
+			if tc.expectedError != nil {
+				assert.Contains(t, buf.String(), "-log.level  LOG_LEVEL  LOG_LEVEL_FILE  string  info     Log level\n")
+			} else {
+				assert.Empty(t, buf.String())
+			}
+		})
+	}
+}
+
+func TestPickWithPrintConfig(t *testing.T) {
+	origExit, origStdout := exit, os.Stdout
+	defer func() {
+		exit, os.Stdout = origExit, origStdout
+	}()
+
+	tests := []struct {
+		name           string
+		opts           func(*flag.FlagSet) []Option
+		expectedExit   bool
+		expectedOutput string
+	}{
+		{
+			name: "NoOption",
+			opts: func(fs *flag.FlagSet) []Option {
+				return []Option{WithFlagSet(fs, []string{"-port=9090"})}
+			},
+			expectedExit:   false,
+			expectedOutput: "",
+		},
+		{
+			name: "NotPassed",
+			opts: func(fs *flag.FlagSet) []Option {
+				return []Option{WithFlagSet(fs, []string{"-port=9090"}), PrintConfig()}
+			},
+			expectedExit:   false,
+			expectedOutput: "",
+		},
+		{
+			name: "Passed",
+			opts: func(fs *flag.FlagSet) []Option {
+				return []Option{WithFlagSet(fs, []string{"-port=9090", "-print-config"}), PrintConfig()}
+			},
+			expectedExit: true,
+			expectedOutput: "" +
+				"FIELD      VALUE  SOURCE\n" +
+				"Name       app    default\n" +
+				"Port       9090   flag\n" +
+				"Password   *****  default\n" +
+				"Internal          default\n" +
+				"Log.Level  info   default\n",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rd, wr, err := os.Pipe()
+			assert.NoError(t, err)
+			os.Stdout = wr
+
+			exited := false
+			exit = func(code int) {
+				exited = true
+				assert.Equal(t, 0, code)
+			}
+
+			fs := flag.NewFlagSet("app", flag.ContinueOnError)
+			c := new(usageConfig)
+			err = Pick(c, tc.opts(fs)...)
+			assert.NoError(t, err)
+
+			assert.NoError(t, wr.Close())
+			out, err := io.ReadAll(rd)
+			assert.NoError(t, err)
+
+			assert.Equal(t, tc.expectedExit, exited)
+			assert.Equal(t, tc.expectedOutput, string(out))
+			assert.Equal(t, 9090, c.Port)
+		})
+	}
+
+	t.Run("InvalidConfig", func(t *testing.T) {
+		rd, wr, err := os.Pipe()
+		assert.NoError(t, err)
+		os.Stdout = wr
+
+		exited := false
+		exit = func(int) {
+			exited = true
+		}
+
+		fs := flag.NewFlagSet("app", flag.ContinueOnError)
+		c := new(usageConfig)
+		err = Pick(c, WithFlagSet(fs, []string{"-port=invalid", "-print-config"}), PrintConfig())
+		assert.Error(t, err)
+
+		assert.NoError(t, wr.Close())
+		out, err := io.ReadAll(rd)
+		assert.NoError(t, err)
+
+		assert.False(t, exited)
+		assert.Empty(t, string(out))
+	})
+}
@@ -0,0 +1,251 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ValidationError is the error for a field value that violates a validation tag.
+type ValidationError struct {
+	// Field is the name of the field.
+	Field string
+	// Tag is the name of the violated validation tag (required, min, max, oneof, or pattern).
+	Tag string
+	// Err describes the violation.
+	Err error
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s failed %s validation: %s", e.Field, e.Tag, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// validation is the set of validation rules specified for a field using struct tags.
+type validation struct {
+	required bool
+	min      string
+	max      string
+	oneof    string
+	pattern  string
+}
+
+// getValidation reads the validation tags of a struct field.
+func getValidation(f reflect.StructField) validation {
+	required, _ := strconv.ParseBool(f.Tag.Get(tagRequired))
+
+	return validation{
+		required: required,
+		min:      f.Tag.Get(tagMin),
+		max:      f.Tag.Get(tagMax),
+		oneof:    f.Tag.Get(tagOneOf),
+		pattern:  f.Tag.Get(tagPattern),
+	}
+}
+
+// validateField checks the current value of a field against its validation rules.
+// A required field must have a non-zero value.
+// The rest of the rules are checked for numbers and durations even if they are zero (i.e. 0 is not a valid number of workers for min:"1"),
+// while they are only checked for other fields with non-zero values.
+func validateField(f fieldInfo) error {
+	v := f.value
+
+	if v.IsZero() {
+		if f.validation.required {
+			return &ValidationError{f.name, tagRequired, errors.New("value is required")}
+		}
+		if !isNumber(v) {
+			return nil
+		}
+	}
+
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+
+	if f.validation.min != "" {
+		if err := checkLimit(v, f.validation.min, f.layout, -1); err != nil {
+			return &ValidationError{f.name, tagMin, err}
+		}
+	}
+
+	if f.validation.max != "" {
+		if err := checkLimit(v, f.validation.max, f.layout, 1); err != nil {
+			return &ValidationError{f.name, tagMax, err}
+		}
+	}
+
+	if f.validation.oneof != "" {
+		if err := checkOneOf(v, strings.Split(f.validation.oneof, "|"), f.secret); err != nil {
+			return &ValidationError{f.name, tagOneOf, err}
+		}
+	}
+
+	if f.validation.pattern != "" {
+		if err := checkPattern(v, f.validation.pattern, f.secret); err != nil {
+			return &ValidationError{f.name, tagPattern, err}
+		}
+	}
+
+	return nil
+}
+
+// isNumber determines whether or not a value is a number (including durations).
+func isNumber(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+
+	return false
+}
+
+// checkLimit compares a value against a limit and returns an error if the value is beyond the limit.
+// A negative direction means the limit is a minimum and a positive direction means the limit is a maximum.
+// Numbers, durations, and times are compared by their values while strings, slices, and maps are compared by their lengths.
+func checkLimit(v reflect.Value, limit, layout string, direction int) error {
+	var cmp int
+	var desc string
+
+	switch {
+	case v.Type() == reflect.TypeOf(time.Duration(0)):
+		d, err := time.ParseDuration(limit)
+		if err != nil {
+			return fmt.Errorf("invalid limit %q: %s", limit, err)
+		}
+		cmp = compare(v.Int(), int64(d))
+		desc = "value"
+
+	case v.Type() == reflect.TypeOf(time.Time{}):
+		t, err := time.Parse(layout, limit)
+		if err != nil {
+			return fmt.Errorf("invalid limit %q: %s", limit, err)
+		}
+		cmp = v.Interface().(time.Time).Compare(t)
+		desc = "value"
+
+	default:
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			i, err := strconv.ParseInt(limit, 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid limit %q: %s", limit, err)
+			}
+			cmp = compare(v.Int(), i)
+			desc = "value"
+
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			u, err := strconv.ParseUint(limit, 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid limit %q: %s", limit, err)
+			}
+			cmp = compare(v.Uint(), u)
+			desc = "value"
+
+		case reflect.Float32, reflect.Float64:
+			f, err := strconv.ParseFloat(limit, 64)
+			if err != nil {
+				return fmt.Errorf("invalid limit %q: %s", limit, err)
+			}
+			cmp = compare(v.Float(), f)
+			desc = "value"
+
+		case reflect.String, reflect.Slice, reflect.Map:
+			i, err := strconv.Atoi(limit)
+			if err != nil {
+				return fmt.Errorf("invalid limit %q: %s", limit, err)
+			}
+			cmp = compare(v.Len(), i)
+			desc = "length"
+
+		default:
+			return fmt.Errorf("unsupported type: %s", v.Type())
+		}
+	}
+
+	switch {
+	case direction < 0 && cmp < 0:
+		return fmt.Errorf("%s must be at least %s", desc, limit)
+	case direction > 0 && cmp > 0:
+		return fmt.Errorf("%s must be at most %s", desc, limit)
+	}
+
+	return nil
+}
+
+func compare[T int | int64 | uint64 | float64](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// quoteValue returns a value quoted for an error or a generic description if the value is secret.
+func quoteValue(secret bool, val string) string {
+	if secret {
+		return "value"
+	}
+	return strconv.Quote(val)
+}
+
+// checkOneOf returns an error if a value (or any element of a slice value) is not one of the allowed values.
+// Secret values are not included in the error.
+func checkOneOf(v reflect.Value, allowed []string, secret bool) error {
+	if v.Kind() == reflect.Slice {
+		for i := 0; i < v.Len(); i++ {
+			if err := checkOneOf(v.Index(i), allowed, secret); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	str := fmt.Sprintf("%v", v.Interface())
+	for _, a := range allowed {
+		if str == a {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%s is not one of %s", quoteValue(secret, str), strings.Join(allowed, ", "))
+}
+
+// checkPattern returns an error if a string value (or any element of a string slice value) does not match a regular expression.
+// Secret values are not included in the error.
+func checkPattern(v reflect.Value, pattern string, secret bool) error {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("invalid pattern %q: %s", pattern, err)
+	}
+
+	switch {
+	case v.Kind() == reflect.String:
+		if !re.MatchString(v.String()) {
+			return fmt.Errorf("%s does not match %s", quoteValue(secret, v.String()), pattern)
+		}
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.String:
+		for i := 0; i < v.Len(); i++ {
+			if err := checkPattern(v.Index(i), pattern, secret); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported type: %s", v.Type())
+	}
+
+	return nil
+}
@@ -0,0 +1,177 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gardenbed/basil/ptr"
+)
+
+func TestValidationError(t *testing.T) {
+	err := &ValidationError{
+		Field: "Port",
+		Tag:   "min",
+		Err:   errors.New("value must be at least 1024"),
+	}
+
+	assert.EqualError(t, err, "Port failed min validation: value must be at least 1024")
+	assert.EqualError(t, err.Unwrap(), "value must be at least 1024")
+}
+
+func TestGetValidation(t *testing.T) {
+	type fields struct {
+		None     string
+		Required string `required:"true"`
+		Range    int    `min:"1" max:"10"`
+		OneOf    string `oneof:"debug|info"`
+		Pattern  string `pattern:"^[a-z]+$"`
+	}
+
+	tests := []struct {
+		field              string
+		expectedValidation validation
+	}{
+		{"None", validation{}},
+		{"Required", validation{required: true}},
+		{"Range", validation{min: "1", max: "10"}},
+		{"OneOf", validation{oneof: "debug|info"}},
+		{"Pattern", validation{pattern: "^[a-z]+$"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.field, func(t *testing.T) {
+			f, ok := reflect.TypeOf(fields{}).FieldByName(tc.field)
+			assert.True(t, ok)
+
+			assert.Equal(t, tc.expectedValidation, getValidation(f))
+		})
+	}
+}
+
+func TestValidateField(t *testing.T) {
+	tests := []struct {
+		name          string
+		value         interface{}
+		v             validation
+		expectedError string
+	}{
+		{"NoRule", "", validation{}, ""},
+		{"RequiredOK", "content", validation{required: true}, ""},
+		{"RequiredZero", "", validation{required: true}, "Field failed required validation: value is required"},
+		{"RequiredNilPointer", (*string)(nil), validation{required: true}, "Field failed required validation: value is required"},
+		{"ZeroIntMin", 0, validation{min: "1"}, "Field failed min validation: value must be at least 1"},
+		{"ZeroIntMinOK", 0, validation{min: "0"}, ""},
+		{"ZeroDurationMin", time.Duration(0), validation{min: "1s"}, "Field failed min validation: value must be at least 1s"},
+		{"ZeroStringSkipsRules", "", validation{min: "1", pattern: "^[a-z]+$"}, ""},
+		{"NilPointerSkipsRules", (*int)(nil), validation{min: "1"}, ""},
+		{"IntMinOK", 8080, validation{min: "1024"}, ""},
+		{"IntMin", 80, validation{min: "1024"}, "Field failed min validation: value must be at least 1024"},
+		{"IntMax", 70000, validation{max: "65535"}, "Field failed max validation: value must be at most 65535"},
+		{"IntPointerMin", ptr.Int(80), validation{min: "1024"}, "Field failed min validation: value must be at least 1024"},
+		{"UintMax", uint8(200), validation{max: "100"}, "Field failed max validation: value must be at most 100"},
+		{"FloatMin", 0.5, validation{min: "1.5"}, "Field failed min validation: value must be at least 1.5"},
+		{"DurationMax", time.Minute, validation{max: "30s"}, "Field failed max validation: value must be at most 30s"},
+		{"TimeMin", time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC), validation{min: "2020-01-01T00:00:00Z"}, "Field failed min validation: value must be at least 2020-01-01T00:00:00Z"},
+		{"StringMinLength", "ab", validation{min: "3"}, "Field failed min validation: length must be at least 3"},
+		{"SliceMaxLength", []string{"a", "b", "c"}, validation{max: "2"}, "Field failed max validation: length must be at most 2"},
+		{"MapMaxLength", map[string]string{"a": "1", "b": "2"}, validation{max: "1"}, "Field failed max validation: length must be at most 1"},
+		{"InvalidLimit", 8080, validation{min: "low"}, `Field failed min validation: invalid limit "low": strconv.ParseInt: parsing "low": invalid syntax`},
+		{"UnsupportedLimit", true, validation{min: "1"}, "Field failed min validation: unsupported type: bool"},
+		{"OneOfOK", "info", validation{oneof: "debug|info|warn"}, ""},
+		{"OneOf", "trace", validation{oneof: "debug|info|warn"}, `Field failed oneof validation: "trace" is not one of debug, info, warn`},
+		{"OneOfInt", 3, validation{oneof: "1|2"}, `Field failed oneof validation: "3" is not one of 1, 2`},
+		{"OneOfSlice", []string{"info", "trace"}, validation{oneof: "debug|info|warn"}, `Field failed oneof validation: "trace" is not one of debug, info, warn`},
+		{"PatternOK", "backend", validation{pattern: "^[a-z]+$"}, ""},
+		{"Pattern", "Backend", validation{pattern: "^[a-z]+$"}, `Field failed pattern validation: "Backend" does not match ^[a-z]+$`},
+		{"PatternSlice", []string{"backend", "Frontend"}, validation{pattern: "^[a-z]+$"}, `Field failed pattern validation: "Frontend" does not match ^[a-z]+$`},
+		{"InvalidPattern", "backend", validation{pattern: "[a-z"}, "Field failed pattern validation: invalid pattern \"[a-z\": error parsing regexp: missing closing ]: `[a-z`"},
+		{"UnsupportedPattern", 8080, validation{pattern: "^[0-9]+$"}, "Field failed pattern validation: unsupported type: int"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			f := fieldInfo{
+				value:      reflect.ValueOf(tc.value),
+				name:       "Field",
+				layout:     time.RFC3339,
+				validation: tc.v,
+			}
+
+			err := validateField(f)
+
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expectedError)
+			}
+		})
+	}
+}
+
+func TestValidateFieldSecret(t *testing.T) {
+	tests := []struct {
+		name          string
+		value         interface{}
+		v             validation
+		expectedError string
+	}{
+		{"OneOf", "s3cr3t", validation{oneof: "a|b"}, "Field failed oneof validation: value is not one of a, b"},
+		{"Pattern", "sk_live_s3cr3t", validation{pattern: "^sk-"}, "Field failed pattern validation: value does not match ^sk-"},
+		{"StringMinLength", "ab", validation{min: "3"}, "Field failed min validation: length must be at least 3"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			f := fieldInfo{
+				value:      reflect.ValueOf(tc.value),
+				name:       "Field",
+				layout:     time.RFC3339,
+				validation: tc.v,
+				secret:     true,
+			}
+
+			assert.EqualError(t, validateField(f), tc.expectedError)
+		})
+	}
+}
+
+func TestPickSecretErrors(t *testing.T) {
+	type secretConfig struct {
+		SecretAPIKey string `secret:"true" pattern:"^sk-"`
+		SecretPIN    int    `secret:"true"`
+	}
+
+	env := map[string]string{
+		"SECRET_API_KEY": "sk_live_SUPERSECRET",
+		"SECRET_PIN":     "pin-SUPERSECRET",
+	}
+
+	for name, val := range env {
+		assert.NoError(t, os.Setenv(name, val))
+	}
+
+	defer func() {
+		for name := range env {
+			assert.NoError(t, os.Unsetenv(name))
+		}
+	}()
+
+	err := Pick(new(secretConfig), SkipFlag())
+	assert.Error(t, err)
+	assert.NotContains(t, err.Error(), "SUPERSECRET")
+
+	var ferr *FieldError
+	assert.True(t, errors.As(err, &ferr))
+	assert.Equal(t, "SecretPIN", ferr.Field)
+	assert.Equal(t, "*****", ferr.Value)
+
+	var verr *ValidationError
+	assert.True(t, errors.As(err, &verr))
+	assert.Equal(t, "SecretAPIKey", verr.Field)
+	assert.EqualError(t, verr, "SecretAPIKey failed pattern validation: value does not match ^sk-")
+}
@@ -0,0 +1,72 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// validator is a function validating the new values of a config struct of a given type as a whole.
+type validator struct {
+	typ reflect.Type
+	fn  func(old, new interface{}) error
+}
+
+// UpdateError is the error for a batch of new values rejected by a validator (see WithValidator option).
+type UpdateError struct {
+	// Fields are the names of the fields with new values.
+	Fields []string
+	// Err is the error returned by the validator.
+	Err error
+}
+
+func (e *UpdateError) Error() string {
+	return fmt.Sprintf("update of %s rejected: %s", strings.Join(e.Fields, ", "), e.Err)
+}
+
+func (e *UpdateError) Unwrap() error {
+	return e.Err
+}
+
+// WithValidator is the option for validating the new values of a config struct as a whole (i.e. a max pool size should not be below the min).
+// The function is called with the current and the new values of the struct before new values are set on the struct.
+// When values are read for the first time, the function is called with a nil old value.
+// When watching, the new values are set on a candidate copy of the struct, and the function is called without holding the lock.
+// If the function returns an error, the new values are rejected, and the struct stays on its previous values (see Watcher.Errors).
+// Otherwise, the new values are copied into the struct while holding the lock.
+// The config struct should be of type *T; otherwise an error is returned.
+func WithValidator[T any](fn func(old, new *T) error) Option {
+	v := validator{
+		typ: reflect.TypeOf((*T)(nil)),
+		fn: func(old, new interface{}) error {
+			o, _ := old.(*T)
+			return fn(o, new.(*T))
+		},
+	}
+
+	return func(c *reader) {
+		c.validators = append(c.validators, v)
+	}
+}
+
+// checkValidators ensures all validators can validate the config struct.
+func (r *reader) checkValidators(config interface{}) error {
+	for _, v := range r.validators {
+		if t := reflect.TypeOf(config); t != v.typ {
+			return fmt.Errorf("cannot validate %s with a validator for %s", t, v.typ)
+		}
+	}
+
+	return nil
+}
+
+// runValidators validates the new values of a config struct using all validators.
+func (r *reader) runValidators(old, new interface{}) error {
+	for _, v := range r.validators {
+		if err := v.fn(old, new); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,217 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type pooled struct {
+	sync.Mutex
+	PoolMin int
+	PoolMax int
+}
+
+func validatePool(old, new *pooled) error {
+	if new.PoolMax < new.PoolMin {
+		return errors.New("max pool size is below min pool size")
+	}
+	return nil
+}
+
+func TestUpdateError(t *testing.T) {
+	err := &UpdateError{
+		Fields: []string{"PoolMin", "PoolMax"},
+		Err:    errors.New("invalid pool size"),
+	}
+
+	assert.EqualError(t, err, "update of PoolMin, PoolMax rejected: invalid pool size")
+	assert.Equal(t, err.Err, errors.Unwrap(err))
+}
+
+func TestPickWithValidator(t *testing.T) {
+	origArgs := os.Args
+	defer func() {
+		os.Args = origArgs
+	}()
+
+	os.Args = []string{"app"}
+
+	assert.NoError(t, os.Setenv("POOL_MIN", "10"))
+	defer func() {
+		assert.NoError(t, os.Unsetenv("POOL_MIN"))
+	}()
+
+	t.Run("InvalidType", func(t *testing.T) {
+		c := new(watched)
+		err := Pick(c, WithValidator(validatePool))
+		assert.EqualError(t, err, "cannot validate *config.watched with a validator for *config.pooled")
+	})
+
+	t.Run("Rejected", func(t *testing.T) {
+		c := &pooled{PoolMax: 5}
+		err := Pick(c, WithValidator(validatePool))
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "max pool size is below min pool size")
+	})
+
+	t.Run("Accepted", func(t *testing.T) {
+		var calledOld *pooled
+		called := false
+
+		c := &pooled{PoolMax: 20}
+		err := Pick(c, WithValidator(func(old, new *pooled) error {
+			called, calledOld = true, old
+			return validatePool(old, new)
+		}))
+
+		assert.NoError(t, err)
+		assert.True(t, called)
+		assert.Nil(t, calledOld)
+		assert.Equal(t, 10, c.PoolMin)
+		assert.Equal(t, 20, c.PoolMax)
+	})
+}
+
+func TestWatchWithValidator(t *testing.T) {
+	origArgs := os.Args
+	defer func() {
+		os.Args = origArgs
+	}()
+
+	os.Args = []string{"app"}
+
+	dir := t.TempDir()
+	minPath := filepath.Join(dir, "min")
+	maxPath := filepath.Join(dir, "max")
+
+	assert.NoError(t, os.WriteFile(minPath, []byte("10"), 0644))
+	assert.NoError(t, os.WriteFile(maxPath, []byte("20"), 0644))
+
+	assert.NoError(t, os.Setenv("POOL_MIN_FILE", minPath))
+	assert.NoError(t, os.Setenv("POOL_MAX_FILE", maxPath))
+	defer func() {
+		assert.NoError(t, os.Unsetenv("POOL_MIN_FILE"))
+		assert.NoError(t, os.Unsetenv("POOL_MAX_FILE"))
+	}()
+
+	ch := make(chan Update, 10)
+	c := new(pooled)
+	w, err := WatchContext(context.Background(), c, []chan Update{ch}, WithValidator(validatePool), PollInterval(time.Hour))
+	assert.NoError(t, err)
+
+	assert.Equal(t, Update{"PoolMin", 10}, <-ch)
+	assert.Equal(t, Update{"PoolMax", 20}, <-ch)
+
+	var oldPool, newPool *pooled
+	assert.NoError(t, OnUpdate(w, func(o, n *pooled) {
+		oldPool, newPool = o, n
+	}))
+
+	t.Run("Rejected", func(t *testing.T) {
+		assert.NoError(t, os.WriteFile(minPath, []byte("30"), 0644))
+		assert.NoError(t, os.WriteFile(maxPath, []byte("25"), 0644))
+		w.Reload()
+
+		err := <-w.Errors()
+		assert.EqualError(t, err, "update of PoolMin, PoolMax rejected: max pool size is below min pool size")
+
+		// The struct should stay on its previous values
+		c.Lock()
+		assert.Equal(t, 10, c.PoolMin)
+		assert.Equal(t, 20, c.PoolMax)
+		c.Unlock()
+
+		assert.Empty(t, ch)
+		assert.Nil(t, oldPool)
+		assert.Nil(t, newPool)
+	})
+
+	t.Run("InvalidValue", func(t *testing.T) {
+		assert.NoError(t, os.WriteFile(minPath, []byte("ten"), 0644))
+		assert.NoError(t, os.WriteFile(maxPath, []byte("20"), 0644))
+		w.Reload()
+
+		err := <-w.Errors()
+		fieldErr := new(FieldError)
+		assert.True(t, errors.As(err, &fieldErr))
+		assert.Equal(t, "PoolMin", fieldErr.Field)
+		assert.Equal(t, "ten", fieldErr.Value)
+
+		assert.Empty(t, ch)
+	})
+
+	t.Run("Accepted", func(t *testing.T) {
+		assert.NoError(t, os.WriteFile(minPath, []byte("15"), 0644))
+		assert.NoError(t, os.WriteFile(maxPath, []byte("25"), 0644))
+		w.Reload()
+
+		assert.Equal(t, Update{"PoolMin", 15}, <-ch)
+		assert.Equal(t, Update{"PoolMax", 25}, <-ch)
+
+		c.Lock()
+		assert.Equal(t, 15, c.PoolMin)
+		assert.Equal(t, 25, c.PoolMax)
+		c.Unlock()
+
+		assert.Equal(t, 10, oldPool.PoolMin)
+		assert.Equal(t, 15, newPool.PoolMin)
+	})
+
+	w.Close()
+
+	_, ok := <-w.Errors()
+	assert.False(t, ok)
+}
+
+func TestWatcherApplyWithValidator(t *testing.T) {
+	dir := t.TempDir()
+	minPath := filepath.Join(dir, "min")
+	assert.NoError(t, os.WriteFile(minPath, []byte("30"), 0644))
+
+	c := &pooled{PoolMin: 10, PoolMax: 20}
+	v := reflect.ValueOf(c).Elem()
+
+	var locked bool
+	var candidate *pooled
+
+	r := &reader{
+		filesToFields: map[string]fieldInfo{
+			minPath: {value: v.FieldByName("PoolMin"), name: "PoolMin"},
+		},
+		validators: []validator{
+			{
+				typ: reflect.TypeOf(c),
+				fn: func(old, new interface{}) error {
+					// The validator should be called without holding the lock
+					if locked = !c.TryLock(); !locked {
+						c.Unlock()
+					}
+
+					candidate = new.(*pooled)
+					return validatePool(old.(*pooled), candidate)
+				},
+			},
+		},
+	}
+
+	w := newWatcher(r, c, c)
+	w.check(minPath)
+
+	assert.False(t, locked)
+
+	// The new value should only be set on the candidate
+	assert.Equal(t, 30, candidate.PoolMin)
+	assert.Equal(t, 10, c.PoolMin)
+	assert.Equal(t, 20, c.PoolMax)
+
+	err := <-w.Errors()
+	assert.EqualError(t, err, "update of PoolMin rejected: max pool size is below min pool size")
+}
@@ -0,0 +1,989 @@
+package config
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+const (
+	// defaultPollInterval is the interval for polling configuration files when the file system cannot be watched.
+	defaultPollInterval = 10 * time.Second
+
+	// errorsBufferSize is the number of errors for rejected updates kept until received (see Watcher.Errors).
+	errorsBufferSize = 16
+)
+
+// subscriber queues updates for a subscriber channel, so updates are delivered in order without blocking the watcher.
+type subscriber struct {
+	ch     chan Update
+	mu     sync.Mutex
+	queue  []Update
+	signal chan struct{}
+}
+
+func newSubscribers(chs []chan Update) []*subscriber {
+	if len(chs) == 0 {
+		return nil
+	}
+
+	subs := make([]*subscriber, len(chs))
+	for i, ch := range chs {
+		subs[i] = &subscriber{
+			ch:     ch,
+			signal: make(chan struct{}, 1),
+		}
+	}
+
+	return subs
+}
+
+// push queues an update for the subscriber.
+func (s *subscriber) push(u Update) {
+	s.mu.Lock()
+	s.queue = append(s.queue, u)
+	s.mu.Unlock()
+
+	select {
+	case s.signal <- struct{}{}:
+	default:
+	}
+}
+
+// pop removes and returns the oldest update in the queue.
+func (s *subscriber) pop() (Update, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.queue) == 0 {
+		return Update{}, false
+	}
+
+	u := s.queue[0]
+	s.queue = s.queue[1:]
+
+	return u, true
+}
+
+// deliver sends queued updates to the subscriber channel in order until the quit channel is closed.
+// Updates not delivered by then are dropped.
+func (s *subscriber) deliver(quit <-chan struct{}) {
+	for {
+		u, ok := s.pop()
+		if !ok {
+			select {
+			case <-quit:
+				return
+			case <-s.signal:
+				continue
+			}
+		}
+
+		select {
+		case <-quit:
+			return
+		case s.ch <- u:
+		}
+	}
+}
+
+// Watcher watches configuration files and sets new values read from them on their fields.
+type Watcher struct {
+	r      *reader
+	config interface{}
+	lock   sync.Locker
+	hashes map[string][sha256.Size]byte
+	dirs   map[string]bool
+	reload chan struct{}
+	full   chan chan struct{}
+	cancel context.CancelFunc
+	stop   chan struct{}
+	done   chan struct{}
+	errs   chan error
+
+	// New values are set on a copy of the config struct before they are copied into the config struct
+	candidate interface{}
+	fields    map[string]fieldInfo
+
+	// All values are read again on a fresh copy of the config struct before any value is read (see Reload)
+	initial interface{}
+
+	mu        sync.Mutex
+	listeners []func(old, new interface{})
+}
+
+func newWatcher(r *reader, config interface{}, lock sync.Locker) *Watcher {
+	w := &Watcher{
+		r:      r,
+		config: config,
+		lock:   lock,
+		hashes: map[string][sha256.Size]byte{},
+		dirs:   map[string]bool{},
+		reload: make(chan struct{}, 1),
+		full:   make(chan chan struct{}),
+		cancel: func() {},
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+		errs:   make(chan error, errorsBufferSize),
+	}
+
+	w.initial = snapshot(config)
+	w.candidate = snapshot(config)
+	w.fields = w.fieldsOf(w.candidate)
+
+	return w
+}
+
+// Close stops the watcher and waits until it is fully stopped.
+func (w *Watcher) Close() {
+	w.cancel()
+	<-w.done
+}
+
+// Done returns a channel that is closed once the watcher is fully stopped and all subscriber channels are closed.
+func (w *Watcher) Done() <-chan struct{} {
+	return w.done
+}
+
+// Errors returns a channel receiving an error for every rejected update.
+// An update is rejected with a *FieldError if a new value cannot be set on its field or fails validation,
+// and with an *UpdateError if a validator rejects a batch of new values (see WithValidator option).
+// Errors reading the values of watched sources (see WatchableSource) are sent too, while the last values of the sources are kept.
+// Errors are dropped if they are not received in time, and the channel is closed once the watcher is fully stopped.
+func (w *Watcher) Errors() <-chan error {
+	return w.errs
+}
+
+// Reload reads the values of all fields again from all sources and sets the changed values on their fields.
+// Subscribers and listeners are only notified for the fields whose values are changed.
+// The .env files and configuration documents are read again too, and the configuration files are watched
+// based on the current values of file environment variables, so the paths to files can be changed without restarting.
+// Fields with no value anymore return to their default values (unless the default values fail validation), new elements of slices and maps of structs are added,
+// and elements no longer in any source are removed.
+// Reload blocks until the values are reloaded or the watcher is stopped.
+// Reloads can also be triggered by signals (see ReloadOnSignal option) or HTTP requests (see ServeHTTP).
+func (w *Watcher) Reload() {
+	done := make(chan struct{})
+
+	select {
+	case w.full <- done:
+	case <-w.stop:
+		return
+	}
+
+	select {
+	case <-done:
+	case <-w.stop:
+	}
+}
+
+// ServeHTTP triggers a reload on a POST request (i.e. on a /-/reload endpoint) and responds once the values are reloaded.
+func (w *Watcher) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		rw.Header().Set("Allow", http.MethodPost)
+		http.Error(rw, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Reload()
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// start starts watching configuration files using either
+//   - polling the files at an interval (see PollInterval option),
+//   - watching the directories of the files (see WatchDir option),
+//   - or watching the files themselves.
+//
+// If the file system cannot be watched, it falls back to polling.
+// The watcher stops when the given context is cancelled.
+func (w *Watcher) start(ctx context.Context) error {
+	// Remember the current contents, so unchanged writes do not cause updates
+	for path := range w.r.filesToFields {
+		if b, err := os.ReadFile(path); err == nil {
+			w.hashes[path] = sha256.Sum256(b)
+		}
+	}
+
+	var run func(context.Context)
+
+	if w.r.pollInterval > 0 {
+		w.r.log(2, "Polling configuration files every %s ...", w.r.pollInterval)
+		run = func(ctx context.Context) {
+			w.poll(ctx, w.r.pollInterval)
+		}
+	} else if watcher, err := fsnotify.NewWatcher(); err != nil {
+		w.r.log(1, "cannot create a watcher, falling back to polling every %s: %s", defaultPollInterval, err)
+		run = func(ctx context.Context) {
+			w.poll(ctx, defaultPollInterval)
+		}
+	} else {
+		if w.r.watchDir {
+			w.r.log(2, "Watching configuration directories ...")
+			err = w.addDirs(watcher)
+		} else {
+			w.r.log(2, "Watching configuration files ...")
+			err = w.addFiles(watcher)
+		}
+
+		if err != nil {
+			_ = watcher.Close()
+			return err
+		}
+
+		run = func(ctx context.Context) {
+			w.watch(ctx, watcher)
+		}
+	}
+
+	ctx, w.cancel = context.WithCancel(ctx)
+
+	// Deliver updates to every subscriber in a separate goroutine
+	quit := make(chan struct{})
+	var wg sync.WaitGroup
+	for _, sub := range w.r.subscribers {
+		wg.Add(1)
+		go func(s *subscriber) {
+			defer wg.Done()
+			s.deliver(quit)
+		}(sub)
+	}
+
+	// Watch sources that can notify about changes
+	var swg sync.WaitGroup
+	for _, src := range w.r.sources {
+		if ws, ok := src.(WatchableSource); ok {
+			w.r.log(2, "Watching %s source ...", ws.Name())
+			swg.Add(1)
+			go func() {
+				defer swg.Done()
+				ws.Watch(ctx, w.notifyReload, func(err error) {
+					w.r.log(1, "%s", err)
+					w.sendError(err)
+				})
+			}()
+		}
+	}
+
+	// Reload all values when a signal is received
+	if len(w.r.reloadSignals) > 0 {
+		sigs := make(chan os.Signal, 1)
+		signal.Notify(sigs, w.r.reloadSignals...)
+
+		swg.Add(1)
+		go func() {
+			defer swg.Done()
+			defer signal.Stop(sigs)
+
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case sig := <-sigs:
+					w.r.log(3, "received signal %s, reloading ...", sig)
+					w.Reload()
+				}
+			}
+		}()
+	}
+
+	go func() {
+		run(ctx)
+		w.cancel()
+		close(w.stop)
+		swg.Wait()
+		w.r.log(2, "Stopped watching configuration files")
+
+		// No more updates will be queued at this point
+		close(quit)
+		wg.Wait()
+
+		for _, sub := range w.r.subscribers {
+			close(sub.ch)
+		}
+
+		close(w.errs)
+
+		close(w.done)
+	}()
+
+	return nil
+}
+
+// notifyReload requests reading the values of all fields again from sources.
+// Multiple requests received while reading are coalesced.
+func (w *Watcher) notifyReload() {
+	select {
+	case w.reload <- struct{}{}:
+	default:
+	}
+}
+
+// addFiles adds a watch for every configuration file.
+func (w *Watcher) addFiles(watcher *fsnotify.Watcher) error {
+	for path := range w.r.filesToFields {
+		if err := watcher.Add(path); err != nil {
+			w.r.log(1, "cannot watch file %s: %s", path, err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// addDirs adds a watch for every directory containing a configuration file or the target of a symlinked configuration file.
+// Directories are re-evaluated on every change, so the new targets of swapped symlinks are watched too.
+func (w *Watcher) addDirs(watcher *fsnotify.Watcher) error {
+	for path := range w.r.filesToFields {
+		dirs := []string{filepath.Dir(path)}
+		if target, err := filepath.EvalSymlinks(path); err == nil {
+			dirs = append(dirs, filepath.Dir(target))
+		}
+
+		for _, dir := range dirs {
+			if w.dirs[dir] {
+				continue
+			}
+
+			if err := watcher.Add(dir); err != nil {
+				w.r.log(1, "cannot watch directory %s: %s", dir, err)
+				return err
+			}
+
+			w.dirs[dir] = true
+			w.r.log(6, "watching directory %s", dir)
+		}
+	}
+
+	return nil
+}
+
+// watch receives file system events until the context is cancelled.
+func (w *Watcher) watch(ctx context.Context, watcher *fsnotify.Watcher) {
+	defer func() {
+		_ = watcher.Close()
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+
+			w.r.log(6, "event received: %s %s", event.Op, event.Name)
+
+			if w.r.watchDir {
+				w.handleDirEvent(watcher, event)
+			} else {
+				w.handleFileEvent(watcher, event)
+			}
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			w.r.log(1, "error watching: %s", err)
+
+		case <-w.reload:
+			w.checkSources()
+
+		case done := <-w.full:
+			w.reloadAll(watcher)
+			close(done)
+		}
+	}
+}
+
+// handleFileEvent handles an event for a watched configuration file.
+func (w *Watcher) handleFileEvent(watcher *fsnotify.Watcher, event fsnotify.Event) {
+	path := filepath.Clean(event.Name)
+
+	// We only receive events for added files, this if check is redundant!
+	if _, ok := w.r.filesToFields[path]; !ok {
+		return
+	}
+
+	if event.Op&fsnotify.Write == fsnotify.Write {
+		w.check(path)
+	}
+
+	// Remove and Rename
+	// Kubernetes injects new values from ConfigMaps and Secrets by removing the mounted files and recreating them.
+	// Many editors and tools also replace files atomically by renaming a new file over them.
+	// When a watched file is removed or replaced, the fsnotify package will remove it from the watcher too.
+	// This if block is a workaround for the aforementioned situations.
+	if event.Op&(fsnotify.Remove|fsnotify.Rename) != 0 {
+		// Check if the removed file is already recreated
+		if _, err := os.Stat(path); err == nil {
+			w.check(path)
+
+			// Re-Add a watch for the file
+			if err := watcher.Add(path); err != nil {
+				w.r.log(1, "cannot watch file %s: %s", path, err)
+			}
+		}
+	}
+}
+
+// handleDirEvent handles an event in a watched directory.
+// Any event in a watched directory (such as swapping the ..data symlink in a Kubernetes volume) may change the configuration files,
+// so all configuration files are checked for new contents.
+func (w *Watcher) handleDirEvent(watcher *fsnotify.Watcher, event fsnotify.Event) {
+	if event.Op == fsnotify.Chmod {
+		return
+	}
+
+	w.checkAll()
+
+	if err := w.addDirs(watcher); err != nil {
+		w.r.log(1, "cannot watch new directories: %s", err)
+	}
+}
+
+// poll checks all configuration files for new contents at an interval until the context is cancelled.
+func (w *Watcher) poll(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.checkAll()
+		case <-w.reload:
+			w.checkSources()
+
+		case done := <-w.full:
+			w.reloadAll(nil)
+			close(done)
+		}
+	}
+}
+
+// checkAll checks all configuration files for new contents.
+// New values from all files are applied together as one batch.
+func (w *Watcher) checkAll() {
+	paths := make([]string, 0, len(w.r.filesToFields))
+	for path := range w.r.filesToFields {
+		paths = append(paths, path)
+	}
+
+	w.check(paths...)
+}
+
+// change is a new value read for a field.
+type change struct {
+	source string
+	path   string
+	f      fieldInfo
+	val    string
+}
+
+// from returns the file path or the source from which the new value is read.
+func (c change) from() string {
+	if c.path != "" {
+		return c.path
+	}
+	return c.source
+}
+
+// check reads configuration files and sets their contents on the corresponding fields if the contents are changed.
+func (w *Watcher) check(paths ...string) {
+	changes := []change{}
+	for _, path := range paths {
+		if f, val, ok := w.read(path); ok {
+			changes = append(changes, change{sourceFile, path, f, val})
+		}
+	}
+
+	w.apply(changes)
+}
+
+// checkSources reads the values of all fields again after the values of a source are changed.
+// Values are read from all sources in order, so a changed value is only set if no source with a higher priority has a value.
+func (w *Watcher) checkSources() {
+	w.r.log(3, "received an update from sources")
+
+	changes := []change{}
+	for _, f := range w.r.fields {
+		// Values from files are checked by watching the files
+		if val, source, _ := w.r.getFieldValue(f); val != "" && source != sourceFile {
+			changes = append(changes, change{source, "", f, val})
+		}
+	}
+
+	w.apply(changes)
+}
+
+// reloadAll reads the values of all fields again from all sources (see Reload).
+// Values are read on a fresh copy of the initial config struct, so new elements are discovered,
+// elements no longer in any source are removed, and fields with no value anymore return to their default values.
+// The new values are diffed against the current values, and the config struct is only updated if any value is changed.
+// The watcher is nil when polling.
+func (w *Watcher) reloadAll(watcher *fsnotify.Watcher) {
+	w.r.log(3, "reloading all values ...")
+
+	// The last values are kept if the files cannot be read
+	if err := w.r.loadEnvFiles(); err != nil {
+		w.r.log(1, "cannot reload environment files: %s", err)
+	}
+
+	if err := w.r.loadDocument(); err != nil {
+		w.r.log(1, "cannot reload configuration document: %s", err)
+	}
+
+	listeners := w.getListeners()
+	old := snapshot(w.candidate)
+	fresh := snapshot(w.initial)
+
+	// Updates for subscribers are held until the new values are copied into the config struct
+	setter := *w.r
+	setter.hold()
+
+	order := []string{}
+	fields := map[string]fieldInfo{}
+	changes := map[string]change{}
+	paths := map[string]string{}
+
+	w.r.iterateOnFields(reflect.ValueOf(fresh).Elem(), func(f fieldInfo) {
+		order = append(order, f.name)
+		fields[f.name] = f
+
+		val, source, path := w.r.getFieldValue(f)
+		c := change{source, path, f, val}
+
+		if path != "" {
+			paths[f.name] = path
+		}
+
+		// Fields start from their current values, so values that cannot be set keep their current values
+		def := reflect.New(f.value.Type()).Elem()
+		def.Set(f.value)
+
+		cur, ok := w.fields[f.name]
+		if ok {
+			f.value.Set(cur.value)
+			if f.store != nil {
+				f.store()
+			}
+		} else {
+			// Fields of new elements
+			changes[f.name] = c
+		}
+
+		if val == "" {
+			if !reflect.DeepEqual(f.value.Interface(), def.Interface()) {
+				w.r.log(3, "[%s] no value anymore, falling back to default value", f.name)
+
+				// The default value is validated like any new value, so an invalid default value keeps the current value
+				fallback := f
+				fallback.value = def
+				if err := validateField(fallback); err != nil {
+					w.reject(change{sourceDefault, "", f, formatValue(def, f.listSep, f.layout)}, "", err)
+					return
+				}
+
+				f.value.Set(def)
+				if f.store != nil {
+					f.store()
+				}
+
+				setter.notifySubscribers(f.name, f.value.Interface())
+				changes[f.name] = c
+			}
+
+			return
+		}
+
+		resolved, err := w.r.resolveValue(f, val)
+		if err != nil {
+			w.reject(c, "", err)
+			return
+		}
+
+		changed, err := setter.updateField(f, resolved)
+		if err != nil {
+			w.reject(c, resolved, err)
+		}
+
+		if changed {
+			changes[f.name] = c
+		}
+	})
+
+	// Fields of elements no longer in any source
+	removed := false
+	for name := range w.fields {
+		if _, ok := fields[name]; !ok {
+			removed = true
+		}
+	}
+
+	if len(changes) == 0 && !removed {
+		w.rewatch(watcher, paths)
+		return
+	}
+
+	new := snapshot(fresh)
+
+	if err := w.r.runValidators(old, new); err != nil {
+		names := []string{}
+		for _, name := range order {
+			if _, ok := changes[name]; ok {
+				names = append(names, name)
+			}
+		}
+
+		uerr := &UpdateError{Fields: names, Err: err}
+		w.r.log(1, "rejected the new values: %s", uerr)
+		w.sendError(uerr)
+
+		return
+	}
+
+	w.lock.Lock()
+
+	// The config struct gets its own copy, so the candidate and the config struct never share nested structs
+	copyStruct(reflect.ValueOf(w.config).Elem(), cloneStruct(reflect.ValueOf(fresh).Elem()))
+
+	// Fields are bound to the config struct again, since nested structs and elements may be replaced
+	current := w.fieldsOf(w.config)
+	w.r.fields = make([]fieldInfo, len(order))
+	for i, name := range order {
+		f := fields[name]
+		f.value, f.store = current[name].value, current[name].store
+		w.r.fields[i] = f
+
+		if c, ok := changes[name]; ok {
+			w.r.reportField(f, c.source, c.path, c.val)
+		}
+	}
+
+	setter.release(true)
+
+	w.lock.Unlock()
+
+	w.candidate, w.fields = fresh, fields
+	w.rewatch(watcher, paths)
+
+	for _, l := range listeners {
+		l(old, new)
+	}
+}
+
+// rewatch updates the configuration files watched for fields after reloading all values,
+// since file environment variables may be changed or unset, and elements may be added or removed.
+// paths are the file paths read for fields by the names of fields.
+func (w *Watcher) rewatch(watcher *fsnotify.Watcher, paths map[string]string) {
+	fields := map[string]fieldInfo{}
+	for _, f := range w.r.fields {
+		fields[f.name] = f
+	}
+
+	for path, f := range w.r.filesToFields {
+		if paths[f.name] != path {
+			w.unwatchFile(watcher, path)
+		}
+	}
+
+	for name, path := range paths {
+		f, ok := fields[name]
+		if !ok {
+			continue
+		}
+
+		if _, ok := w.r.filesToFields[path]; ok {
+			w.r.filesToFields[path] = f
+		} else {
+			w.watchFile(watcher, path, f)
+		}
+
+		w.remember(path)
+	}
+}
+
+// remember remembers the current content of a configuration file, so unchanged writes do not cause updates.
+func (w *Watcher) remember(path string) {
+	if b, err := os.ReadFile(path); err == nil {
+		w.hashes[path] = sha256.Sum256(b)
+	}
+}
+
+// watchFile starts watching a new configuration file for a field.
+func (w *Watcher) watchFile(watcher *fsnotify.Watcher, path string, f fieldInfo) {
+	w.r.log(3, "[%s] watching new file %s", f.name, path)
+
+	w.r.filesToFields[path] = f
+
+	if watcher == nil {
+		return
+	}
+
+	var err error
+	if w.r.watchDir {
+		err = w.addDirs(watcher)
+	} else {
+		err = watcher.Add(path)
+	}
+
+	if err != nil {
+		w.r.log(1, "cannot watch file %s: %s", path, err)
+	}
+}
+
+// unwatchFile stops watching a configuration file no longer used for a field.
+func (w *Watcher) unwatchFile(watcher *fsnotify.Watcher, path string) {
+	w.r.log(3, "[%s] stopped watching file %s", w.r.filesToFields[path].name, path)
+
+	delete(w.r.filesToFields, path)
+	delete(w.hashes, path)
+
+	if watcher != nil && !w.r.watchDir {
+		_ = watcher.Remove(path)
+	}
+}
+
+// apply sets new values on their fields together as one batch.
+// New values are set and validated on the candidate copy of the config struct without holding the lock.
+// If any validator rejects the candidate (see WithValidator option), all new values in the batch are rolled back on the candidate.
+// Otherwise, the new values are copied into the config struct while holding the lock.
+// Listeners are called once for the batch if any value is changed.
+func (w *Watcher) apply(changes []change) {
+	if len(changes) == 0 {
+		return
+	}
+
+	listeners := w.getListeners()
+	validate := len(w.r.validators) > 0
+
+	var old interface{}
+	if len(listeners) > 0 || validate {
+		old = snapshot(w.candidate)
+	}
+
+	// Updates for subscribers are held until the new values are copied into the config struct
+	setter := *w.r
+	setter.hold()
+
+	type update struct {
+		change
+		candidate fieldInfo
+		prev      reflect.Value
+	}
+
+	updates := []update{}
+
+	for _, c := range changes {
+		f, ok := w.fields[c.f.name]
+		if !ok {
+			continue
+		}
+
+		val, err := w.r.resolveValue(c.f, c.val)
+		if err != nil {
+			w.reject(c, "", err)
+			continue
+		}
+
+		prev := reflect.New(f.value.Type()).Elem()
+		prev.Set(f.value)
+
+		ok, err = setter.updateField(f, val)
+		if err != nil {
+			w.reject(c, val, err)
+		}
+
+		if ok {
+			updates = append(updates, update{c, f, prev})
+		}
+	}
+
+	if len(updates) == 0 {
+		return
+	}
+
+	var new interface{}
+	if len(listeners) > 0 || validate {
+		new = snapshot(w.candidate)
+	}
+
+	if validate {
+		if err := w.r.runValidators(old, new); err != nil {
+			// Roll back the new values on the candidate in reverse order
+			for i := len(updates) - 1; i >= 0; i-- {
+				updates[i].candidate.value.Set(updates[i].prev)
+				if updates[i].candidate.store != nil {
+					updates[i].candidate.store()
+				}
+			}
+
+			fields := make([]string, len(updates))
+			for i, u := range updates {
+				fields[i] = u.f.name
+			}
+
+			uerr := &UpdateError{Fields: fields, Err: err}
+			w.r.log(1, "rejected the new values: %s", uerr)
+			w.sendError(uerr)
+
+			return
+		}
+	}
+
+	w.lock.Lock()
+
+	for _, u := range updates {
+		u.f.value.Set(u.candidate.value)
+		if u.f.store != nil {
+			u.f.store()
+		}
+
+		w.r.reportField(u.f, u.source, u.path, u.val)
+	}
+
+	setter.release(true)
+
+	w.lock.Unlock()
+
+	for _, l := range listeners {
+		l(old, new)
+	}
+}
+
+// fieldsOf returns the fields of a copy of the config struct by their names.
+// New elements are not discovered, so the copy keeps the same elements as the config struct.
+func (w *Watcher) fieldsOf(config interface{}) map[string]fieldInfo {
+	quiet := *w.r
+	quiet.skipFlag, quiet.skipEnv, quiet.skipFileEnv = true, true, true
+	quiet.doc, quiet.profileDoc = nil, nil
+
+	fields := map[string]fieldInfo{}
+	quiet.iterateOnFields(reflect.ValueOf(config).Elem(), func(f fieldInfo) {
+		fields[f.name] = f
+	})
+
+	return fields
+}
+
+// reject logs a new value that cannot be set on its field and sends an error for it.
+// The resolved value is the value after resolving a reference to a secret if any.
+func (w *Watcher) reject(c change, resolved string, err error) {
+	val := c.val
+	if c.f.secret || w.r.isReference(val) {
+		val = maskValue(true, val)
+		err = maskError(err, c.val, resolved)
+	}
+
+	w.r.log(1, "rejected the value from %s: %s", c.from(), err)
+
+	w.sendError(&FieldError{
+		Field:  c.f.name,
+		Source: c.source,
+		Value:  val,
+		Err:    err,
+	})
+}
+
+// sendError sends an error for a rejected update without blocking the watcher.
+// Errors are dropped if the error channel is full.
+func (w *Watcher) sendError(err error) {
+	select {
+	case w.errs <- err:
+	default:
+	}
+}
+
+// read reads a configuration file and returns its content if the content is changed.
+func (w *Watcher) read(path string) (fieldInfo, string, bool) {
+	f, ok := w.r.filesToFields[path]
+	if !ok {
+		return fieldInfo{}, "", false
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		w.r.log(1, "cannot read file %s: %s", path, err)
+		return fieldInfo{}, "", false
+	}
+
+	// An empty file has no value (same as when reading fields), and it may also be a file being written
+	if len(b) == 0 {
+		w.r.log(6, "no value in %s", path)
+		return fieldInfo{}, "", false
+	}
+
+	hash := sha256.Sum256(b)
+	if prev, ok := w.hashes[path]; ok && prev == hash {
+		w.r.log(6, "no change in %s", path)
+		return fieldInfo{}, "", false
+	}
+	w.hashes[path] = hash
+
+	val := w.r.expandValue(f, string(b))
+	w.r.protectValue(f, val)
+	w.r.log(3, "received an update from %s: %s", path, maskValue(f.secret, val))
+
+	return f, val, true
+}
+
+// addListener adds a function to be called with the old and new snapshots of the config struct after every batch of changes.
+func (w *Watcher) addListener(l func(old, new interface{})) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.listeners = append(w.listeners, l)
+}
+
+func (w *Watcher) getListeners() []func(old, new interface{}) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.listeners
+}
+
+// snapshot returns a pointer to a copy of the config struct.
+func snapshot(config interface{}) interface{} {
+	return cloneStruct(reflect.ValueOf(config).Elem()).Addr().Interface()
+}
+
+// OnUpdate registers a function to be called with the old and new snapshots of the config struct whenever new values are set.
+// All new values read at once (e.g. from all files swapped in a Kubernetes volume) are reported together in one call.
+// The snapshots are copies of the config struct and can be read without locking.
+// The function is called on the watcher goroutine, so it should not block.
+// The config passed to the watcher should be of type *C; otherwise an error is returned.
+func OnUpdate[C any](w *Watcher, fn func(old, new *C)) error {
+	if _, ok := w.config.(*C); !ok {
+		return fmt.Errorf("cannot listen to %T on a watcher for %T", (*C)(nil), w.config)
+	}
+
+	w.addListener(func(old, new interface{}) {
+		fn(old.(*C), new.(*C))
+	})
+
+	return nil
+}
+
+// OnChange registers a function to be called with the old and new values selected by get whenever the selected value changes.
+// The get function is called on snapshots of the config struct and can select a field, a nested struct, or any value derived from them.
+// The function is called on the watcher goroutine, so it should not block.
+// The config passed to the watcher should be of type *C; otherwise an error is returned.
+func OnChange[C, T any](w *Watcher, get func(*C) T, fn func(old, new T)) error {
+	return OnUpdate(w, func(old, new *C) {
+		if o, n := get(old), get(new); !reflect.DeepEqual(o, n) {
+			fn(o, n)
+		}
+	})
+}
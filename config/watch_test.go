@@ -0,0 +1,711 @@
+package config
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type watched struct {
+	sync.Mutex
+	WatchLevel string
+}
+
+func (w *watched) get() string {
+	w.Lock()
+	defer w.Unlock()
+	return w.WatchLevel
+}
+
+// nonStruct is a sync.Locker that is not a struct.
+type nonStruct int
+
+func (*nonStruct) Lock()   {}
+func (*nonStruct) Unlock() {}
+
+func TestSubscriber(t *testing.T) {
+	t.Run("DeliverInOrder", func(t *testing.T) {
+		ch := make(chan Update)
+		sub := newSubscribers([]chan Update{ch})[0]
+
+		quit := make(chan struct{})
+		stopped := make(chan struct{})
+		go func() {
+			sub.deliver(quit)
+			close(stopped)
+		}()
+
+		for i := 0; i < 100; i++ {
+			sub.push(Update{"Field", i})
+		}
+
+		for i := 0; i < 100; i++ {
+			assert.Equal(t, Update{"Field", i}, <-ch)
+		}
+
+		close(quit)
+		<-stopped
+	})
+
+	t.Run("QuitWhileBlocked", func(t *testing.T) {
+		ch := make(chan Update)
+		sub := newSubscribers([]chan Update{ch})[0]
+		sub.push(Update{"Field", 1})
+
+		quit := make(chan struct{})
+		stopped := make(chan struct{})
+		go func() {
+			sub.deliver(quit)
+			close(stopped)
+		}()
+
+		close(quit)
+
+		select {
+		case <-stopped:
+		case <-time.After(time.Second):
+			t.Fatal("subscriber did not stop")
+		}
+	})
+}
+
+func TestFileWatcherCheck(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "level")
+	assert.NoError(t, os.WriteFile(path, []byte("info"), 0644))
+
+	c := new(watched)
+	r := &reader{
+		subscribers: newSubscribers([]chan Update{
+			make(chan Update),
+		}),
+		filesToFields: map[string]fieldInfo{
+			path: {
+				value: reflect.ValueOf(c).Elem().FieldByName("WatchLevel"),
+				name:  "WatchLevel",
+			},
+		},
+	}
+
+	w := newWatcher(r, c, c)
+	sub := r.subscribers[0]
+
+	// The first check sets the current content
+	w.check(path)
+	assert.Equal(t, "info", c.get())
+	assert.Equal(t, []Update{{"WatchLevel", "info"}}, sub.queue)
+	sub.queue = nil
+
+	// Unchanged content should not cause an update
+	assert.NoError(t, os.WriteFile(path, []byte("info"), 0644))
+	w.check(path)
+	assert.Empty(t, sub.queue)
+
+	// Changed content should cause an update
+	assert.NoError(t, os.WriteFile(path, []byte("debug"), 0644))
+	w.check(path)
+	assert.Equal(t, "debug", c.get())
+	assert.Equal(t, []Update{{"WatchLevel", "debug"}}, sub.queue)
+	sub.queue = nil
+
+	// Missing files and unknown paths should be ignored
+	assert.NoError(t, os.Remove(path))
+	w.check(path)
+	w.check("/unknown")
+	assert.Equal(t, "debug", c.get())
+	assert.Empty(t, sub.queue)
+}
+
+func TestWatchModes(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   []Option
+		setup  func(t *testing.T, dir string) string
+		update func(t *testing.T, dir string)
+	}{
+		{
+			name: "WatchFiles",
+			opts: nil,
+			setup: func(t *testing.T, dir string) string {
+				path := filepath.Join(dir, "level")
+				assert.NoError(t, os.WriteFile(path, []byte("info"), 0644))
+				return path
+			},
+			update: func(t *testing.T, dir string) {
+				assert.NoError(t, os.WriteFile(filepath.Join(dir, "level"), []byte("debug"), 0644))
+			},
+		},
+		{
+			name: "WatchFilesWithAtomicRename",
+			opts: nil,
+			setup: func(t *testing.T, dir string) string {
+				path := filepath.Join(dir, "level")
+				assert.NoError(t, os.WriteFile(path, []byte("info"), 0644))
+				return path
+			},
+			update: func(t *testing.T, dir string) {
+				tmp := filepath.Join(dir, "level.tmp")
+				assert.NoError(t, os.WriteFile(tmp, []byte("debug"), 0644))
+				assert.NoError(t, os.Rename(tmp, filepath.Join(dir, "level")))
+			},
+		},
+		{
+			name: "WatchDirsWithAtomicRename",
+			opts: []Option{WatchDir()},
+			setup: func(t *testing.T, dir string) string {
+				path := filepath.Join(dir, "level")
+				assert.NoError(t, os.WriteFile(path, []byte("info"), 0644))
+				return path
+			},
+			update: func(t *testing.T, dir string) {
+				tmp := filepath.Join(dir, "level.tmp")
+				assert.NoError(t, os.WriteFile(tmp, []byte("debug"), 0644))
+				assert.NoError(t, os.Rename(tmp, filepath.Join(dir, "level")))
+			},
+		},
+		{
+			// Simulating how Kubernetes updates ConfigMap and Secret volumes
+			name: "WatchDirsWithSymlinkSwap",
+			opts: []Option{WatchDir()},
+			setup: func(t *testing.T, dir string) string {
+				assert.NoError(t, os.Mkdir(filepath.Join(dir, "..v1"), 0755))
+				assert.NoError(t, os.WriteFile(filepath.Join(dir, "..v1", "level"), []byte("info"), 0644))
+				assert.NoError(t, os.Symlink("..v1", filepath.Join(dir, "..data")))
+				assert.NoError(t, os.Symlink(filepath.Join("..data", "level"), filepath.Join(dir, "level")))
+				return filepath.Join(dir, "level")
+			},
+			update: func(t *testing.T, dir string) {
+				assert.NoError(t, os.Mkdir(filepath.Join(dir, "..v2"), 0755))
+				assert.NoError(t, os.WriteFile(filepath.Join(dir, "..v2", "level"), []byte("debug"), 0644))
+				assert.NoError(t, os.Symlink("..v2", filepath.Join(dir, "..data_tmp")))
+				assert.NoError(t, os.Rename(filepath.Join(dir, "..data_tmp"), filepath.Join(dir, "..data")))
+				assert.NoError(t, os.RemoveAll(filepath.Join(dir, "..v1")))
+			},
+		},
+		{
+			name: "PollFiles",
+			opts: []Option{PollInterval(20 * time.Millisecond)},
+			setup: func(t *testing.T, dir string) string {
+				path := filepath.Join(dir, "level")
+				assert.NoError(t, os.WriteFile(path, []byte("info"), 0644))
+				return path
+			},
+			update: func(t *testing.T, dir string) {
+				assert.NoError(t, os.WriteFile(filepath.Join(dir, "level"), []byte("debug"), 0644))
+			},
+		},
+	}
+
+	origArgs := os.Args
+	defer func() {
+		os.Args = origArgs
+	}()
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			os.Args = []string{"app"}
+
+			dir := t.TempDir()
+			path := tc.setup(t, dir)
+
+			err := os.Setenv("WATCH_LEVEL_FILE", path)
+			assert.NoError(t, err)
+
+			defer func() {
+				assert.NoError(t, os.Unsetenv("WATCH_LEVEL_FILE"))
+			}()
+
+			c := new(watched)
+			close, err := Watch(c, nil, tc.opts...)
+			assert.NoError(t, err)
+			defer close()
+
+			assert.Equal(t, "info", c.get())
+
+			tc.update(t, dir)
+
+			assert.Eventually(t, func() bool {
+				return c.get() == "debug"
+			}, 2*time.Second, 10*time.Millisecond)
+		})
+	}
+}
+
+func TestWatchContext(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "level")
+	assert.NoError(t, os.WriteFile(path, []byte("info"), 0644))
+
+	err := os.Setenv("WATCH_LEVEL_FILE", path)
+	assert.NoError(t, err)
+
+	defer func() {
+		assert.NoError(t, os.Unsetenv("WATCH_LEVEL_FILE"))
+	}()
+
+	origArgs := os.Args
+	defer func() {
+		os.Args = origArgs
+	}()
+
+	os.Args = []string{"app"}
+
+	t.Run("InvalidConfig", func(t *testing.T) {
+		w, err := WatchContext(context.Background(), new(nonStruct), nil)
+		assert.EqualError(t, err, "a non-struct type is passed")
+		assert.Nil(t, w)
+	})
+
+	t.Run("Cancel", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		// A subscriber that never receives updates should not block stopping the watcher
+		blocked := make(chan Update)
+		ch := make(chan Update)
+
+		c := new(watched)
+		w, err := WatchContext(ctx, c, []chan Update{ch, blocked})
+		assert.NoError(t, err)
+
+		assert.Equal(t, Update{"WatchLevel", "info"}, <-ch)
+
+		for _, level := range []string{"debug", "warn", "error"} {
+			assert.NoError(t, os.WriteFile(path, []byte(level), 0644))
+			assert.Equal(t, Update{"WatchLevel", level}, <-ch)
+		}
+
+		cancel()
+
+		select {
+		case <-w.Done():
+		case <-time.After(time.Second):
+			t.Fatal("watcher did not stop")
+		}
+
+		// Subscriber channels should be closed
+		_, ok := <-ch
+		assert.False(t, ok)
+		_, ok = <-blocked
+		assert.False(t, ok)
+
+		// No more updates after stopping
+		assert.NoError(t, os.WriteFile(path, []byte("info"), 0644))
+		time.Sleep(50 * time.Millisecond)
+		assert.Equal(t, "error", c.get())
+	})
+
+	t.Run("Close", func(t *testing.T) {
+		ch := make(chan Update, 10)
+
+		w, err := WatchContext(context.Background(), new(watched), []chan Update{ch})
+		assert.NoError(t, err)
+
+		w.Close()
+		w.Close()
+
+		<-w.Done()
+		for range ch {
+		}
+	})
+}
+
+func TestWatcherListeners(t *testing.T) {
+	type server struct {
+		Port int
+	}
+
+	type listened struct {
+		sync.Mutex
+		Level  string
+		Format string
+		Server server
+	}
+
+	dir := t.TempDir()
+	levelPath := filepath.Join(dir, "level")
+	formatPath := filepath.Join(dir, "format")
+	portPath := filepath.Join(dir, "port")
+
+	c := &listened{
+		Level:  "info",
+		Format: "json",
+		Server: server{Port: 8080},
+	}
+
+	v := reflect.ValueOf(c).Elem()
+	r := &reader{
+		filesToFields: map[string]fieldInfo{
+			levelPath:  {value: v.FieldByName("Level"), name: "Level"},
+			formatPath: {value: v.FieldByName("Format"), name: "Format"},
+			portPath:   {value: v.FieldByName("Server").FieldByName("Port"), name: "Server.Port"},
+		},
+	}
+
+	w := newWatcher(r, c, c)
+
+	t.Run("TypeMismatch", func(t *testing.T) {
+		err := OnUpdate(w, func(old, new *watched) {})
+		assert.EqualError(t, err, "cannot listen to *config.watched on a watcher for *config.listened")
+
+		err = OnChange(w, func(c *watched) string { return c.WatchLevel }, func(old, new string) {})
+		assert.EqualError(t, err, "cannot listen to *config.watched on a watcher for *config.listened")
+	})
+
+	type event struct {
+		old, new *listened
+	}
+
+	var updates []event
+	err := OnUpdate(w, func(old, new *listened) {
+		updates = append(updates, event{old, new})
+	})
+	assert.NoError(t, err)
+
+	var levels [][2]string
+	err = OnChange(w, func(c *listened) string { return c.Level }, func(old, new string) {
+		levels = append(levels, [2]string{old, new})
+	})
+	assert.NoError(t, err)
+
+	var servers [][2]server
+	err = OnChange(w, func(c *listened) server { return c.Server }, func(old, new server) {
+		servers = append(servers, [2]server{old, new})
+	})
+	assert.NoError(t, err)
+
+	t.Run("Batch", func(t *testing.T) {
+		updates, levels, servers = nil, nil, nil
+
+		assert.NoError(t, os.WriteFile(levelPath, []byte("debug"), 0644))
+		assert.NoError(t, os.WriteFile(formatPath, []byte("text"), 0644))
+		w.check(levelPath, formatPath)
+
+		// Changes from both files should be reported together
+		assert.Len(t, updates, 1)
+		assert.Equal(t, "info", updates[0].old.Level)
+		assert.Equal(t, "json", updates[0].old.Format)
+		assert.Equal(t, "debug", updates[0].new.Level)
+		assert.Equal(t, "text", updates[0].new.Format)
+
+		assert.Equal(t, [][2]string{{"info", "debug"}}, levels)
+		assert.Empty(t, servers)
+	})
+
+	t.Run("NestedField", func(t *testing.T) {
+		updates, levels, servers = nil, nil, nil
+
+		assert.NoError(t, os.WriteFile(portPath, []byte("9090"), 0644))
+		w.check(portPath)
+
+		assert.Len(t, updates, 1)
+		assert.Empty(t, levels)
+		assert.Equal(t, [][2]server{{{Port: 8080}, {Port: 9090}}}, servers)
+	})
+
+	t.Run("NoChange", func(t *testing.T) {
+		updates, levels, servers = nil, nil, nil
+
+		// Same contents
+		w.check(levelPath, formatPath, portPath)
+
+		// Rejected value
+		assert.NoError(t, os.WriteFile(portPath, []byte("port"), 0644))
+		w.check(portPath)
+
+		assert.Empty(t, updates)
+		assert.Empty(t, levels)
+		assert.Empty(t, servers)
+		assert.Equal(t, 9090, c.Server.Port)
+	})
+}
+
+func TestWatchOnChange(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "level")
+	assert.NoError(t, os.WriteFile(path, []byte("info"), 0644))
+
+	err := os.Setenv("WATCH_LEVEL_FILE", path)
+	assert.NoError(t, err)
+
+	defer func() {
+		assert.NoError(t, os.Unsetenv("WATCH_LEVEL_FILE"))
+	}()
+
+	origArgs := os.Args
+	defer func() {
+		os.Args = origArgs
+	}()
+
+	os.Args = []string{"app"}
+
+	c := new(watched)
+	w, err := WatchContext(context.Background(), c, nil)
+	assert.NoError(t, err)
+	defer w.Close()
+
+	changes := make(chan [2]string, 1)
+	err = OnChange(w, func(c *watched) string { return c.WatchLevel }, func(old, new string) {
+		changes <- [2]string{old, new}
+	})
+	assert.NoError(t, err)
+
+	assert.NoError(t, os.WriteFile(path, []byte("debug"), 0644))
+
+	select {
+	case change := <-changes:
+		assert.Equal(t, [2]string{"info", "debug"}, change)
+	case <-time.After(2 * time.Second):
+		t.Fatal("no change received")
+	}
+}
+
+func TestWatchSources(t *testing.T) {
+	var body atomic.Value
+	body.Store(`{ "watch_level": "info" }`)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(body.Load().(string)))
+	}))
+	defer ts.Close()
+
+	origArgs := os.Args
+	defer func() {
+		os.Args = origArgs
+	}()
+
+	os.Args = []string{"app"}
+
+	src, err := NewHTTPSource(context.Background(), ts.URL, SourceRefreshInterval(10*time.Millisecond))
+	assert.NoError(t, err)
+
+	ch := make(chan Update, 10)
+	c := new(watched)
+	w, err := WatchContext(context.Background(), c, []chan Update{ch}, WithSource(src))
+	assert.NoError(t, err)
+	defer w.Close()
+
+	assert.Equal(t, "info", c.get())
+	assert.Equal(t, Update{"WatchLevel", "info"}, <-ch)
+
+	body.Store(`{ "watch_level": "debug" }`)
+	assert.Equal(t, Update{"WatchLevel", "debug"}, <-ch)
+	assert.Equal(t, "debug", c.get())
+
+	// A missing value should not unset the field
+	body.Store(`{ "watch_level": "" }`)
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, "debug", c.get())
+
+	// Errors fetching values should be reported and the last values should be kept
+	body.Store(`[`)
+	err = <-w.Errors()
+	assert.EqualError(t, err, "cannot refresh http source: cannot parse "+ts.URL+": unexpected EOF")
+	assert.Equal(t, "debug", c.get())
+}
+
+func TestWatcherReload(t *testing.T) {
+	type reloaded struct {
+		sync.Mutex
+		ReloadLevel string
+		ReloadCert  string
+		ReloadPort  int
+	}
+
+	origArgs := os.Args
+	defer func() {
+		os.Args = origArgs
+	}()
+
+	os.Args = []string{"app"}
+
+	dir := t.TempDir()
+	envPath := filepath.Join(dir, ".env")
+	certPath := filepath.Join(dir, "cert")
+	newCertPath := filepath.Join(dir, "new-cert")
+
+	assert.NoError(t, os.WriteFile(envPath, []byte("RELOAD_LEVEL=info\nRELOAD_PORT=8080\n"), 0644))
+	assert.NoError(t, os.WriteFile(certPath, []byte("cert"), 0644))
+	assert.NoError(t, os.WriteFile(newCertPath, []byte("new-cert"), 0644))
+
+	assert.NoError(t, os.Setenv("RELOAD_CERT_FILE", certPath))
+	defer func() {
+		assert.NoError(t, os.Unsetenv("RELOAD_CERT_FILE"))
+	}()
+
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{"WatchFiles", []Option{WithEnvFile(envPath)}},
+		{"WatchDir", []Option{WithEnvFile(envPath), WatchDir()}},
+		{"Poll", []Option{WithEnvFile(envPath), PollInterval(time.Hour)}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.NoError(t, os.WriteFile(envPath, []byte("RELOAD_LEVEL=info\nRELOAD_PORT=8080\n"), 0644))
+			assert.NoError(t, os.Setenv("RELOAD_CERT_FILE", certPath))
+
+			ch := make(chan Update, 10)
+			c := new(reloaded)
+			w, err := WatchContext(context.Background(), c, []chan Update{ch}, tc.opts...)
+			assert.NoError(t, err)
+
+			assert.Equal(t, Update{"ReloadLevel", "info"}, <-ch)
+			assert.Equal(t, Update{"ReloadCert", "cert"}, <-ch)
+			assert.Equal(t, Update{"ReloadPort", 8080}, <-ch)
+
+			// Only changed values should be notified
+			assert.NoError(t, os.WriteFile(envPath, []byte("RELOAD_LEVEL=debug\nRELOAD_PORT=8080\n"), 0644))
+			assert.NoError(t, os.Setenv("RELOAD_CERT_FILE", newCertPath))
+			w.Reload()
+
+			assert.Equal(t, Update{"ReloadLevel", "debug"}, <-ch)
+			assert.Equal(t, Update{"ReloadCert", "new-cert"}, <-ch)
+
+			c.Lock()
+			assert.Equal(t, "debug", c.ReloadLevel)
+			assert.Equal(t, "new-cert", c.ReloadCert)
+			assert.Equal(t, 8080, c.ReloadPort)
+			c.Unlock()
+
+			// The new file should be watched instead of the old one
+			w.Reload()
+			assert.Contains(t, w.r.filesToFields, newCertPath)
+			assert.NotContains(t, w.r.filesToFields, certPath)
+
+			// Values no longer set should return to their defaults
+			assert.NoError(t, os.WriteFile(envPath, []byte("RELOAD_LEVEL=debug\n"), 0644))
+			assert.NoError(t, os.Unsetenv("RELOAD_CERT_FILE"))
+			w.Reload()
+
+			assert.Equal(t, Update{"ReloadCert", ""}, <-ch)
+			assert.Equal(t, Update{"ReloadPort", 0}, <-ch)
+
+			c.Lock()
+			assert.Equal(t, "debug", c.ReloadLevel)
+			assert.Empty(t, c.ReloadCert)
+			assert.Equal(t, 0, c.ReloadPort)
+			c.Unlock()
+
+			assert.NotContains(t, w.r.filesToFields, newCertPath)
+
+			w.Close()
+
+			// Reloading a stopped watcher should not block
+			w.Reload()
+
+			_, ok := <-ch
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestWatcherReloadInvalidDefault(t *testing.T) {
+	type reloaded struct {
+		sync.Mutex
+		ReloadHost string `required:"true"`
+	}
+
+	origArgs := os.Args
+	defer func() {
+		os.Args = origArgs
+	}()
+
+	os.Args = []string{"app"}
+
+	envPath := filepath.Join(t.TempDir(), ".env")
+	assert.NoError(t, os.WriteFile(envPath, []byte("RELOAD_HOST=localhost\n"), 0644))
+
+	ch := make(chan Update, 10)
+	c := new(reloaded)
+	w, err := WatchContext(context.Background(), c, []chan Update{ch}, WithEnvFile(envPath))
+	assert.NoError(t, err)
+	defer w.Close()
+
+	assert.Equal(t, Update{"ReloadHost", "localhost"}, <-ch)
+
+	// A default value failing validation should be rejected
+	assert.NoError(t, os.WriteFile(envPath, []byte(""), 0644))
+	w.Reload()
+
+	var ferr *FieldError
+	assert.ErrorAs(t, <-w.Errors(), &ferr)
+	assert.Equal(t, "ReloadHost", ferr.Field)
+	assert.Equal(t, sourceDefault, ferr.Source)
+	assert.EqualError(t, ferr.Err, "ReloadHost failed required validation: value is required")
+
+	c.Lock()
+	assert.Equal(t, "localhost", c.ReloadHost)
+	c.Unlock()
+
+	assert.Empty(t, ch)
+}
+
+func TestWatcherServeHTTP(t *testing.T) {
+	origArgs := os.Args
+	defer func() {
+		os.Args = origArgs
+	}()
+
+	os.Args = []string{"app"}
+
+	c := new(watched)
+	w, err := WatchContext(context.Background(), c, nil)
+	assert.NoError(t, err)
+	defer w.Close()
+
+	t.Run("MethodNotAllowed", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		w.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/-/reload", nil))
+		assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+		assert.Equal(t, http.MethodPost, rec.Header().Get("Allow"))
+	})
+
+	t.Run("Reload", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		w.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/-/reload", nil))
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+	})
+}
+
+func TestWatchReloadOnSignal(t *testing.T) {
+	origArgs := os.Args
+	defer func() {
+		os.Args = origArgs
+	}()
+
+	os.Args = []string{"app"}
+
+	envPath := filepath.Join(t.TempDir(), ".env")
+	assert.NoError(t, os.WriteFile(envPath, []byte("WATCH_LEVEL=info\n"), 0644))
+
+	ch := make(chan Update, 10)
+	c := new(watched)
+	w, err := WatchContext(context.Background(), c, []chan Update{ch}, WithEnvFile(envPath), ReloadOnSignal())
+	assert.NoError(t, err)
+	defer w.Close()
+
+	assert.Equal(t, Update{"WatchLevel", "info"}, <-ch)
+
+	assert.NoError(t, os.WriteFile(envPath, []byte("WATCH_LEVEL=debug\n"), 0644))
+
+	p, err := os.FindProcess(os.Getpid())
+	assert.NoError(t, err)
+	assert.NoError(t, p.Signal(syscall.SIGHUP))
+
+	select {
+	case u := <-ch:
+		assert.Equal(t, Update{"WatchLevel", "debug"}, u)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no update received after the signal")
+	}
+}